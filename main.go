@@ -1,26 +1,45 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
+	"image"
 	"image/color"
+	"image/jpeg"
+	"image/png"
 	"io"
+	"log"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/driver/software"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	ical "github.com/arran4/golang-ical"
+	"github.com/fsnotify/fsnotify"
 )
 
 // --- Constants & Data ---
@@ -32,6 +51,40 @@ const (
 	TypeEvent ItemType = "Event"
 )
 
+type Priority string
+
+const (
+	PriorityLow    Priority = "Low"
+	PriorityMedium Priority = "Medium"
+	PriorityHigh   Priority = "High"
+)
+
+// Availability says whether an event blocks time; empty means Busy.
+type Availability string
+
+const (
+	AvailabilityBusy Availability = "Busy"
+	AvailabilityFree Availability = "Free"
+)
+
+// Outlook writes Windows zone names into TZID.
+var windowsTimezones = map[string]string{
+	"UTC":                          "UTC",
+	"GMT Standard Time":            "Europe/London",
+	"W. Europe Standard Time":      "Europe/Berlin",
+	"Romance Standard Time":        "Europe/Paris",
+	"Central Europe Standard Time": "Europe/Budapest",
+	"E. Europe Standard Time":      "Europe/Chisinau",
+	"Eastern Standard Time":        "America/New_York",
+	"Central Standard Time":        "America/Chicago",
+	"Mountain Standard Time":       "America/Denver",
+	"Pacific Standard Time":        "America/Los_Angeles",
+	"India Standard Time":          "Asia/Kolkata",
+	"China Standard Time":          "Asia/Shanghai",
+	"Tokyo Standard Time":          "Asia/Tokyo",
+	"AUS Eastern Standard Time":    "Australia/Sydney",
+}
+
 var PresetColors = []string{
 	"#E74C3C", "#E67E22", "#F1C40F", "#2ECC71",
 	"#1ABC9C", "#3498DB", "#9B59B6", "#34495E",
@@ -39,58 +92,191 @@ var PresetColors = []string{
 }
 
 type Group struct {
-	ID       string `json:"id"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ColorHex  string `json:"color"`
+	SortMode  string `json:"sortMode"`
+	SortOrder int    `json:"sortOrder,omitempty"`
+	WipLimit  int    `json:"wipLimit,omitempty"`
+	ParentID  string `json:"parentId,omitempty"`
+}
+
+// CalendarMeta is one entry of calendars_meta.json; the data files are still keyed by Name.
+type CalendarMeta struct {
 	Name     string `json:"name"`
-	ColorHex string `json:"color"`
-	SortMode string `json:"sortMode"`
+	ColorHex string `json:"color,omitempty"`
+	Icon     string `json:"icon,omitempty"`
+}
+
+// UnmarshalJSON also accepts a bare name, which is how the calendar list was stored before it had metadata.
+func (c *CalendarMeta) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '"' {
+		*c = CalendarMeta{}
+		return json.Unmarshal(trimmed, &c.Name)
+	}
+	type plain CalendarMeta
+	return json.Unmarshal(b, (*plain)(c))
 }
 
 type TodoItem struct {
-	ID        string   `json:"id"`
-	Title     string   `json:"title"`
-	Start     string   `json:"start"`
-	End       string   `json:"end"`
-	Type      ItemType `json:"type"`
-	GroupID   string   `json:"groupId"`
-	GroupName string   `json:"group,omitempty"`
-	Completed bool     `json:"completed"`
-	SeriesID  string   `json:"seriesId,omitempty"`
+	ID              string          `json:"id"`
+	Title           string          `json:"title"`
+	Start           string          `json:"start"`
+	End             string          `json:"end"`
+	Type            ItemType        `json:"type"`
+	GroupID         string          `json:"groupId"`
+	GroupIDs        []string        `json:"groupIds,omitempty"`
+	GroupName       string          `json:"group,omitempty"`
+	Completed       bool            `json:"completed"`
+	SeriesID        string          `json:"seriesId,omitempty"`
+	SourceUID       string          `json:"sourceUid,omitempty"`
+	Exceptions      []string        `json:"exceptions,omitempty"`
+	RecurrenceRule  *recurrenceRule `json:"recurrenceRule,omitempty"`
+	Priority        Priority        `json:"priority,omitempty"`
+	Availability    Availability    `json:"availability,omitempty"`
+	ColorHex        string          `json:"color,omitempty"`
+	AllDay          bool            `json:"allDay,omitempty"`
+	Archived        bool            `json:"archived,omitempty"`
+	Pinned          bool            `json:"pinned,omitempty"`
+	ReminderMinutes int             `json:"reminderMinutes,omitempty"`
+	Subtasks        []Subtask       `json:"subtasks,omitempty"`
+	Tags            []string        `json:"tags,omitempty"`
+	CompletedAt     string          `json:"completedAt,omitempty"`
+	CreatedAt       string          `json:"createdAt,omitempty"`
+	Attachments     []string        `json:"attachments,omitempty"`
+	Calendar        string          `json:"-"`
+	ImportGroups    []string        `json:"-"`
+}
+
+type Subtask struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+type AppSettings struct {
+	MaxItemsPerCell int                      `json:"maxItemsPerCell"`
+	BackupCount     int                      `json:"backupCount"`
+	Use24Hour       bool                     `json:"use24Hour"`
+	Language        string                   `json:"language,omitempty"`
+	CalDAV          map[string]calDAVAccount `json:"caldav,omitempty"`
+	MinuteStep      int                      `json:"minuteStep"`
+	ShowArchived    bool                     `json:"showArchived"`
+	CompactCards    bool                     `json:"compactCards,omitempty"`
+	InboxEnabled    bool                     `json:"inboxEnabled,omitempty"`
+	WatchFiles      bool                     `json:"watchFiles,omitempty"`
+	LastTab         int                      `json:"lastTab,omitempty"`
+	ViewMode        string                   `json:"viewMode,omitempty"`
+	WorkStart       string                   `json:"workStart,omitempty"`
+	WorkEnd         string                   `json:"workEnd,omitempty"`
+	WeekendDays     []int                    `json:"weekendDays"`
+	DefaultType     string                   `json:"defaultType,omitempty"`
+	DefaultGroup    string                   `json:"defaultGroup,omitempty"`
+	DefaultStart    string                   `json:"defaultStart,omitempty"`
+	DefaultDuration int                      `json:"defaultDuration,omitempty"`
+	AutoComplete    bool                     `json:"autoComplete"`
+	HiddenGroups    map[string][]string      `json:"hiddenGroups,omitempty"`
+	CollapsedGroups map[string][]string      `json:"collapsedGroups,omitempty"`
+	CustomColors    []string                 `json:"customColors,omitempty"`
+	TextScale       int                      `json:"textScale,omitempty"`
+	AccentColor     string                   `json:"accentColor,omitempty"`
 }
 
 // Global Data
 var items []TodoItem
 var groups []Group
-var availableCalendars []string
+var availableCalendars []CalendarMeta
 var activeCalendarName string = "Default"
+
+const allCalendarsName = "All Calendars"
+
+const (
+	icsPropGroup     = ical.ComponentProperty("X-SKC-GROUP")
+	icsPropCompleted = ical.ComponentProperty("X-SKC-COMPLETED")
+)
+
 var currentViewDate time.Time
 var selectedCalendarDate time.Time
 var currentTheme string = "Dark"
+var searchQuery string
+var tagFilter string
+var tagFilterSelect *widget.Select
+var appSettings = AppSettings{MaxItemsPerCell: 3, BackupCount: 10, MinuteStep: 5, WeekendDays: []int{int(time.Saturday), int(time.Sunday)}}
+var lastBackupAt = make(map[string]time.Time)
+var reminders = reminderState{Fired: map[string]time.Time{}, Snoozed: map[string]time.Time{}}
+var invalidDateIDs = make(map[string]bool)
+var overdueColor = color.RGBA{231, 76, 60, 255}
+var dataDir = "."
+var timePickerFormatters []func()
 
 // UI Globals
 var myApp fyne.App
 var mainWindow fyne.Window
 var calendarGrid *fyne.Container
+var weekdayHeaderGrid *fyne.Container
 var kanbanContainer *fyne.Container
 var monthLabel *widget.Label
+var kanbanDropTargets []kanbanDropTarget
+var calendarDropTargets []calendarDropTarget
+var miniCalendar *fyne.Container
+var groupFilterBar *fyne.Container
+var miniViewDate time.Time
+var miniSyncedDate time.Time
+var statsContainer *fyne.Container
+var statsPeriod = "This Week"
+var agendaContainer *fyne.Container
+
+// pinIcon marks pinned kanban cards; the theme has no pin glyph.
+var pinIcon = fyne.NewStaticResource("pin.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#555555" d="M16 9V4h1c.55 0 1-.45 1-1s-.45-1-1-1H7c-.55 0-1 .45-1 1s.45 1 1 1h1v5c0 1.66-1.34 3-3 3v2h5.97v7l1 1 1-1v-7H19v-2c-1.66 0-3-1.34-3-3z"/></svg>`))
+var calendarMode = "Month"
+var monthView, weekView *fyne.Container
+var mainTabs *container.AppTabs
+var weekHeader, weekBody *fyne.Container
+var weekScroll *container.Scroll
+var weekNowLine *canvas.Rectangle
+var weekNowDay time.Time
 
 // Sidebar Globals
 var sbTitleEntry *widget.Entry
 var sbGroupSelect *widget.Select
 var sbTypeSelect *widget.Select
+var sbPrioritySelect *widget.Select
+var sbShowAsSelect *widget.Select
+var sbColorHex string
+var sbColorRect *canvas.Rectangle
+var sbAllDayCheck *widget.Check
+var sbReminderSelect *widget.Select
+var sbConflictLabel *widget.Label
+var sbSubtasks []Subtask
+var sbSubtaskBox *fyne.Container
+var sbTagsEntry *widget.Entry
+var sbAttachments []string
+var sbAttachmentBox *fyne.Container
+var sbAlsoGroups []string
+var sbAlsoGroupsBtn *widget.Button
 var sbActionBtn *widget.Button
 var sbCancelBtn *widget.Button
 var sbDeleteBtn *widget.Button
 var sbHeaderLabel *widget.Label
+var sbSavedLabel *widget.Label
+var sbDurationLabel *widget.Label
+var saveTimer *time.Timer
+var sidebarLoading bool
+var savedHideTimer *time.Timer
 var currentEditItemID string
+var editOriginal TodoItem
 
 // Recurrence Globals
 var recCheck *widget.Check
+var recAdvanceCheck *widget.Check
 var recContainer *fyne.Container
 var recModeRadio *widget.RadioGroup
 var recNumEntry *widget.Entry
 var recUnitSelect *widget.Select
 var recOrdinalSelect *widget.Select
 var recDaySelect *widget.Select
+var recNthSelect *widget.Select
+var recNthDaySelect *widget.Select
+var recMonthsEntry *widget.Entry
 
 // Date/Time Setters
 var setTaskDate func(string)
@@ -111,47 +297,142 @@ var getEndTimeVal func() (string, string, string)
 // --- Main Entry ---
 
 func main() {
+	if runHeadless() {
+		return
+	}
 	myApp = app.New()
-	myApp.Settings().SetTheme(theme.DarkTheme())
 	currentTheme = "Dark"
 
 	mainWindow = myApp.NewWindow("Go Local Calendar & Kanban")
 	mainWindow.Resize(fyne.NewSize(1300, 850))
 
+	initDataDir()
+	loadSettings()
+	loadReminderState()
+	applyTheme()
 	loadCalendarList()
 	loadGroups()
 	loadData()
+	startFileWatch()
+	updateWindowTitle()
 	currentViewDate = time.Now()
 	selectedCalendarDate = time.Now()
 
 	settingsBtn := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
 		showSettingsDialog()
 	})
-	topBar := container.NewHBox(layout.NewSpacer(), settingsBtn)
+	searchEntry := widget.NewEntry()
+	searchEntry.PlaceHolder = "Search..."
+	searchEntry.OnChanged = func(s string) {
+		searchQuery = strings.TrimSpace(s)
+		refreshCalendar()
+		refreshKanban()
+	}
+	tagFilterSelect = widget.NewSelect(nil, func(s string) {
+		if s == "All Tags" {
+			s = ""
+		}
+		if s != tagFilter {
+			tagFilter = s
+			refreshCalendar()
+			refreshKanban()
+		}
+	})
+	tagFilterSelect.PlaceHolder = "All Tags"
+	groupFilterBar = container.NewHBox()
+	topBar := container.NewBorder(nil, nil, nil, container.NewHBox(tagFilterSelect, settingsBtn), container.NewVBox(searchEntry, container.NewHScroll(groupFilterBar)))
 
 	sidebar := createSidebar()
 	calendarView := createCalendarArea()
 	kanbanView := createKanbanArea()
+	statsView := createStatsArea()
+	agendaView := createAgendaArea()
 
-	tabs := container.NewAppTabs(
+	mainTabs = container.NewAppTabs(
 		container.NewTabItemWithIcon("Calendar", theme.ContentPasteIcon(), calendarView),
 		container.NewTabItemWithIcon("Kanban Board", theme.GridIcon(), kanbanView),
+		container.NewTabItemWithIcon("Stats", theme.InfoIcon(), statsView),
+		container.NewTabItemWithIcon("This Week", theme.ListIcon(), agendaView),
 	)
 
-	tabs.OnSelected = func(ti *container.TabItem) {
+	if appSettings.LastTab > 0 && appSettings.LastTab < len(mainTabs.Items) {
+		mainTabs.SelectIndex(appSettings.LastTab)
+	}
+	mainTabs.OnSelected = func(ti *container.TabItem) {
+		if i := mainTabs.SelectedIndex(); i != appSettings.LastTab {
+			appSettings.LastTab = i
+			saveSettings()
+		}
 		refreshCalendar()
 		refreshKanban()
 	}
 
-	split := container.NewHSplit(sidebar, tabs)
+	split := container.NewHSplit(sidebar, mainTabs)
 	split.SetOffset(0.35)
 
 	content := container.NewBorder(topBar, nil, nil, nil, split)
+	tooltipLayer = container.NewWithoutLayout()
 
-	mainWindow.SetContent(content)
+	mainWindow.SetContent(container.NewStack(content, tooltipLayer))
+	mainWindow.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		showCalendarSwitcher()
+	})
+	startClockTicker()
+	mainWindow.SetOnClosed(flushPendingSave)
 	mainWindow.ShowAndRun()
 }
 
+// runHeadless handles the scripting flags (-list-calendars, -export) and reports whether the GUI should be skipped.
+// A bare -calendar just picks which calendar the GUI opens on.
+func runHeadless() bool {
+	exportPath := flag.String("export", "", "write the calendar as .ics to this path and exit")
+	calName := flag.String("calendar", "", "calendar to open or export (default: the first calendar)")
+	listCalendars := flag.Bool("list-calendars", false, "print the calendar names and exit")
+	flag.Parse()
+	initDataDir()
+	if err := readJSONFile(dataPath("calendars_meta.json"), &availableCalendars); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "reading calendar list:", err)
+		os.Exit(1)
+	}
+	if len(availableCalendars) == 0 {
+		availableCalendars = []CalendarMeta{{Name: "Default"}}
+	}
+	if *calName != "" {
+		if calendarIndex(*calName) < 0 {
+			fmt.Fprintf(os.Stderr, "no calendar named %q (use -list-calendars)\n", *calName)
+			os.Exit(1)
+		}
+		activeCalendarName = *calName
+	} else if *exportPath != "" {
+		activeCalendarName = availableCalendars[0].Name
+	}
+	if *listCalendars {
+		for _, name := range calendarNames() {
+			fmt.Println(name)
+		}
+		return true
+	}
+	if *exportPath == "" {
+		return false
+	}
+	dataFile, groupFile := getFilenames()
+	if err := readJSONFile(groupFile, &groups); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "reading", groupFile+":", err)
+		os.Exit(1)
+	}
+	var err error
+	if items, _, err = readItemsFile(dataFile, groups); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "reading", dataFile+":", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*exportPath, []byte(buildICSCalendar(items).Serialize()), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "writing export:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %d items from %q to %s\n", len(items), activeCalendarName, *exportPath)
+	return true
+}
+
 // --- CUSTOM WIDGETS (DEFINED HERE TO PREVENT ERRORS) ---
 
 type clickableBox struct {
@@ -159,6 +440,7 @@ type clickableBox struct {
 	content *fyne.Container
 	onTap   func()
 	onRight func(*fyne.PointEvent)
+	tooltip string
 }
 
 func newClickableBox(c *fyne.Container, fn func()) *clickableBox {
@@ -172,6 +454,7 @@ func (b *clickableBox) CreateRenderer() fyne.WidgetRenderer {
 }
 
 func (b *clickableBox) Tapped(_ *fyne.PointEvent) {
+	hideTooltip()
 	if b.onTap != nil {
 		b.onTap()
 	}
@@ -183,6 +466,338 @@ func (b *clickableBox) TappedSecondary(e *fyne.PointEvent) {
 	}
 }
 
+func (b *clickableBox) MouseIn(e *desktop.MouseEvent) {
+	if b.tooltip != "" {
+		showTooltip(b.tooltip, e.AbsolutePosition)
+	}
+}
+
+func (b *clickableBox) MouseMoved(e *desktop.MouseEvent) {
+	if b.tooltip != "" {
+		moveTooltip(e.AbsolutePosition)
+	}
+}
+
+func (b *clickableBox) MouseOut() {
+	if b.tooltip != "" {
+		hideTooltip()
+	}
+}
+
+type draggableBox struct {
+	clickableBox
+	onDrag    func(*fyne.DragEvent)
+	onDragEnd func()
+}
+
+func newDraggableBox(c *fyne.Container, fn func()) *draggableBox {
+	b := &draggableBox{clickableBox: clickableBox{content: c, onTap: fn}}
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *draggableBox) Dragged(e *fyne.DragEvent) {
+	if b.onDrag != nil {
+		b.onDrag(e)
+	}
+}
+
+func (b *draggableBox) DragEnd() {
+	if b.onDragEnd != nil {
+		b.onDragEnd()
+	}
+}
+
+// resizeHandle is a draggableBox that shows a vertical resize cursor.
+type resizeHandle struct {
+	draggableBox
+}
+
+func newResizeHandle(c *fyne.Container) *resizeHandle {
+	h := &resizeHandle{draggableBox: draggableBox{clickableBox: clickableBox{content: c}}}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+func (h *resizeHandle) Cursor() desktop.Cursor {
+	return desktop.VResizeCursor
+}
+
+// Tooltips are drawn in a layer above the window content rather than as a pop-up, since an overlay would steal the hover.
+var tooltipLayer *fyne.Container
+
+func showTooltip(text string, abs fyne.Position) {
+	if tooltipLayer == nil {
+		return
+	}
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	bg.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	bg.StrokeWidth = 1
+	bg.CornerRadius = 4
+	box := container.NewStack(bg, container.NewPadded(widget.NewLabel(text)))
+	box.Resize(box.MinSize())
+	tooltipLayer.Objects = []fyne.CanvasObject{box}
+	moveTooltip(abs)
+}
+func moveTooltip(abs fyne.Position) {
+	if tooltipLayer == nil || len(tooltipLayer.Objects) == 0 {
+		return
+	}
+	box := tooltipLayer.Objects[0]
+	pos := abs.Subtract(fyne.CurrentApp().Driver().AbsolutePositionForObject(tooltipLayer)).Add(fyne.NewPos(14, 18))
+	if limit := tooltipLayer.Size(); limit.Width > 0 {
+		pos.X = max(0, min(pos.X, limit.Width-box.Size().Width))
+		if pos.Y+box.Size().Height > limit.Height {
+			pos.Y = max(0, pos.Y-box.Size().Height-30)
+		}
+	}
+	box.Move(pos)
+	tooltipLayer.Refresh()
+}
+func hideTooltip() {
+	if tooltipLayer == nil || len(tooltipLayer.Objects) == 0 {
+		return
+	}
+	tooltipLayer.Objects = nil
+	tooltipLayer.Refresh()
+}
+
+// kanbanCard is a draggable card that can also take keyboard focus: arrows move between cards, Enter edits,
+// Space toggles completion and Ctrl/Cmd+Left/Right moves the item to the neighbouring column.
+type kanbanCard struct {
+	draggableBox
+	item     *TodoItem
+	groupID  string
+	col, row int
+	ring     *canvas.Rectangle
+	focused  bool
+	modifier fyne.KeyModifier
+}
+
+// selectedItemIDs is the kanban multi-selection that the selection bar's actions apply to.
+var selectedItemIDs = map[string]bool{}
+var selectionBar *fyne.Container
+var selectionLabel *widget.Label
+
+// kanbanCards holds the cards of the last refresh; col indexes kanbanDropTargets.
+var kanbanCards []*kanbanCard
+
+// kanbanRefocusID names the item whose card should take focus after the next refresh.
+var kanbanRefocusID string
+
+func newKanbanCard(body *fyne.Container, item *TodoItem, groupID string, col, row int) *kanbanCard {
+	ring := canvas.NewRectangle(color.Transparent)
+	ring.CornerRadius = 5
+	c := &kanbanCard{item: item, groupID: groupID, col: col, row: row, ring: ring}
+	c.content = container.NewStack(body, ring)
+	c.onTap = func() { startEditing(item) }
+	c.ExtendBaseWidget(c)
+	c.updateRing()
+	return c
+}
+
+// updateRing outlines the card for keyboard focus, or more heavily when it's part of the multi-selection.
+func (c *kanbanCard) updateRing() {
+	switch {
+	case selectedItemIDs[c.item.ID]:
+		c.ring.StrokeColor = theme.Color(theme.ColorNamePrimary)
+		c.ring.StrokeWidth = 3
+	case c.focused:
+		c.ring.StrokeColor = theme.Color(theme.ColorNameFocus)
+		c.ring.StrokeWidth = 2
+	default:
+		c.ring.StrokeWidth = 0
+	}
+	c.ring.Refresh()
+}
+
+func (c *kanbanCard) FocusGained() {
+	c.focused = true
+	c.updateRing()
+}
+
+func (c *kanbanCard) FocusLost() {
+	c.focused = false
+	c.updateRing()
+}
+
+func (c *kanbanCard) MouseDown(e *desktop.MouseEvent) {
+	c.modifier = e.Modifier
+}
+
+func (c *kanbanCard) MouseUp(*desktop.MouseEvent) {}
+
+// Tapped with Ctrl/Cmd held toggles the card in the multi-selection instead of opening it.
+func (c *kanbanCard) Tapped(e *fyne.PointEvent) {
+	if c.modifier&(fyne.KeyModifierControl|fyne.KeyModifierSuper) != 0 {
+		c.modifier = 0
+		hideTooltip()
+		if selectedItemIDs[c.item.ID] {
+			delete(selectedItemIDs, c.item.ID)
+		} else {
+			selectedItemIDs[c.item.ID] = true
+		}
+		c.updateRing()
+		refreshSelectionBar()
+		return
+	}
+	clearItemSelection()
+	c.clickableBox.Tapped(e)
+}
+
+func (c *kanbanCard) TypedRune(rune) {}
+
+func (c *kanbanCard) TypedKey(e *fyne.KeyEvent) {
+	switch e.Name {
+	case fyne.KeyUp:
+		focusKanbanCard(c.col, c.row-1)
+	case fyne.KeyDown:
+		focusKanbanCard(c.col, c.row+1)
+	case fyne.KeyLeft, fyne.KeyRight:
+		dir := 1
+		if e.Name == fyne.KeyLeft {
+			dir = -1
+		}
+		for col := c.col + dir; col >= 0 && col < len(kanbanDropTargets); col += dir {
+			if focusKanbanCard(col, c.row) {
+				break
+			}
+		}
+	case fyne.KeyReturn, fyne.KeyEnter:
+		startEditing(c.item)
+	case fyne.KeySpace:
+		if blockReadOnly() {
+			return
+		}
+		setCompleted(c.item, !c.item.Completed)
+		saveData()
+		kanbanRefocusID = c.item.ID
+		refreshCalendar()
+		refreshKanban()
+	case fyne.KeyEscape:
+		clearItemSelection()
+		mainWindow.Canvas().Unfocus()
+	}
+}
+
+func (c *kanbanCard) TypedShortcut(s fyne.Shortcut) {
+	cs, ok := s.(*desktop.CustomShortcut)
+	if !ok || cs.Modifier&(fyne.KeyModifierControl|fyne.KeyModifierSuper) == 0 {
+		return
+	}
+	dir := 0
+	switch cs.KeyName {
+	case fyne.KeyLeft:
+		dir = -1
+	case fyne.KeyRight:
+		dir = 1
+	}
+	target := c.col + dir
+	if dir == 0 || target < 0 || target >= len(kanbanDropTargets) || blockReadOnly() {
+		return
+	}
+	to := kanbanDropTargets[target].groupID
+	if slices.Contains(itemGroupIDs(c.item), to) {
+		return
+	}
+	replaceItemGroup(c.item, c.groupID, to)
+	saveData()
+	kanbanRefocusID = c.item.ID
+	refreshCalendar()
+	refreshKanban()
+}
+
+// focusKanbanCard focuses the card at row in column col, clamping the row; it reports false for empty columns.
+func focusKanbanCard(col, row int) bool {
+	var best *kanbanCard
+	for _, k := range kanbanCards {
+		if k.col == col && k.row <= row && (best == nil || k.row > best.row) {
+			best = k
+		}
+	}
+	if best == nil {
+		return false
+	}
+	mainWindow.Canvas().Focus(best)
+	return true
+}
+
+type kanbanDropTarget struct {
+	groupID   string
+	area      fyne.CanvasObject
+	highlight *canvas.Rectangle
+}
+
+type calendarDropTarget struct {
+	day       time.Time
+	area      fyne.CanvasObject
+	highlight *canvas.Rectangle
+}
+
+// progressLayout stretches its first object as the track and sizes the second to the filled fraction.
+type progressLayout struct {
+	fraction float32
+}
+
+func (p *progressLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	objects[0].Move(fyne.NewPos(0, 0))
+	objects[0].Resize(size)
+	objects[1].Move(fyne.NewPos(0, 0))
+	objects[1].Resize(fyne.NewSize(size.Width*p.fraction, size.Height))
+}
+func (p *progressLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(0, 4)
+}
+
+func objectContains(o fyne.CanvasObject, p fyne.Position) bool {
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(o)
+	size := o.Size()
+	return p.X >= pos.X && p.X <= pos.X+size.Width && p.Y >= pos.Y && p.Y <= pos.Y+size.Height
+}
+
+// accentTheme wraps the light or dark theme and swaps in the user's primary color.
+type accentTheme struct {
+	fyne.Theme
+	accent color.Color
+}
+
+func (t *accentTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if name == theme.ColorNamePrimary {
+		return t.accent
+	}
+	return t.Theme.Color(name, variant)
+}
+func applyTheme() {
+	base := theme.DarkTheme()
+	if currentTheme == "Light" {
+		base = theme.LightTheme()
+	}
+	if hex, ok := normalizeHexColor(appSettings.AccentColor); ok {
+		base = &accentTheme{Theme: base, accent: parseHexColor(hex)}
+	}
+	myApp.Settings().SetTheme(base)
+}
+func textScalePercent() int {
+	if appSettings.TextScale <= 0 {
+		return 100
+	}
+	return appSettings.TextScale
+}
+func scaledText(size float32) float32 {
+	return size * float32(textScalePercent()) / 100
+}
+
+func combineDateTime(dateStr, h, m, ap string) string {
+	hour, _ := strconv.Atoi(h)
+	if ap == "PM" && hour != 12 {
+		hour += 12
+	}
+	if ap == "AM" && hour == 12 {
+		hour = 0
+	}
+	return fmt.Sprintf("%s %02d:%s", dateStr, hour, m)
+}
+
 func createStrikethroughText(text string, col color.Color, textSize float32) *fyne.Container {
 	txt := canvas.NewText(text, col)
 	txt.TextSize = textSize
@@ -196,7 +811,7 @@ func createStrikethroughText(text string, col color.Color, textSize float32) *fy
 // --- AUTO SAVE LOGIC ---
 
 func autoSave() {
-	if currentEditItemID == "" {
+	if currentEditItemID == "" || sidebarLoading {
 		return
 	}
 	if sbTitleEntry.Text == "" {
@@ -215,54 +830,82 @@ func autoSave() {
 	}
 
 	targetItem.Title = sbTitleEntry.Text
+	prevStart, prevEnd := targetItem.Start, targetItem.End
 
 	for _, g := range groups {
 		if g.Name == sbGroupSelect.Selected {
-			targetItem.GroupID = g.ID
+			setItemGroups(targetItem, append([]string{g.ID}, sbAlsoGroups...))
 			break
 		}
 	}
+	if appSettings.InboxEnabled && sbGroupSelect.Selected == inboxGroupName {
+		setItemGroups(targetItem, append([]string{""}, sbAlsoGroups...))
+	}
 
 	targetItem.Type = TypeTask
 	if sbTypeSelect.Selected == "Event" {
 		targetItem.Type = TypeEvent
 	}
-
-	combine := func(dateStr, h, m, ap string) string {
-		hour, _ := strconv.Atoi(h)
-		if ap == "PM" && hour != 12 {
-			hour += 12
-		}
-		if ap == "AM" && hour == 12 {
-			hour = 0
-		}
-		return fmt.Sprintf("%s %02d:%s", dateStr, hour, m)
+	targetItem.Priority = Priority(sbPrioritySelect.Selected)
+	targetItem.Availability = sidebarAvailability()
+	targetItem.ColorHex = sbColorHex
+	targetItem.ReminderMinutes = reminderMinutesFromLabel(sbReminderSelect.Selected)
+	wasDone := allSubtasksDone(targetItem.Subtasks)
+	targetItem.Subtasks = slices.Clone(sbSubtasks)
+	targetItem.Tags = parseTags(sbTagsEntry.Text)
+	targetItem.Attachments = slices.Clone(sbAttachments)
+	if appSettings.AutoComplete && !wasDone && allSubtasksDone(targetItem.Subtasks) {
+		setCompleted(targetItem, true)
 	}
 
 	if targetItem.Type == TypeTask {
 		if getTaskTimeVal != nil && getTaskDateVal != nil {
 			h, m, ap := getTaskTimeVal()
-			targetItem.Start = combine(getTaskDateVal(), h, m, ap)
+			targetItem.Start = combineDateTime(getTaskDateVal(), h, m, ap)
 			targetItem.End = targetItem.Start
 		}
 	} else {
 		if getStartTimeVal != nil && getStartDateVal != nil && getEndTimeVal != nil && getEndDateVal != nil {
 			hS, mS, apS := getStartTimeVal()
-			targetItem.Start = combine(getStartDateVal(), hS, mS, apS)
+			targetItem.Start = combineDateTime(getStartDateVal(), hS, mS, apS)
 			hE, mE, apE := getEndTimeVal()
-			targetItem.End = combine(getEndDateVal(), hE, mE, apE)
+			targetItem.End = combineDateTime(getEndDateVal(), hE, mE, apE)
+		}
+	}
+	targetItem.AllDay = sbAllDayCheck.Checked
+	if targetItem.AllDay {
+		targetItem.Start = targetItem.Start[:10] + " 00:00"
+		if targetItem.Type == TypeEvent {
+			targetItem.End = targetItem.End[:10] + " 23:59"
+		} else {
+			targetItem.End = targetItem.Start
+		}
+	}
+	if targetItem.Type == TypeEvent {
+		if fixed := correctEventEnd(prevStart, prevEnd, targetItem.Start, targetItem.End, targetItem.AllDay); fixed != targetItem.End {
+			targetItem.End = fixed
+			e, _ := parseItemTime(fixed)
+			eh, em, eap := formatTimeParts(e)
+			setEndDate(e.Format("2006-01-02"))
+			setEndTime(eh, em, eap)
+			updateDurationLabel()
 		}
 	}
 
-	saveData()
+	delete(invalidDateIDs, targetItem.ID)
+	scheduleSave()
 	refreshCalendar()
 	refreshKanban()
+	updateConflictWarning(*targetItem)
 }
 
 // --- SIDEBAR UI ---
 
 func createSidebar() fyne.CanvasObject {
 	sbHeaderLabel = widget.NewLabelWithStyle("Add New Item", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	sbSavedLabel = widget.NewLabel("Saved")
+	sbSavedLabel.Importance = widget.LowImportance
+	sbSavedLabel.Hide()
 	sbTitleEntry = widget.NewEntry()
 	sbTitleEntry.PlaceHolder = "Title"
 	sbTitleEntry.OnChanged = func(s string) { autoSave() }
@@ -282,11 +925,29 @@ func createSidebar() fyne.CanvasObject {
 			showGroupForm(nil)
 			sbGroupSelect.SetSelected("")
 		} else {
+			sbAlsoGroups = slices.DeleteFunc(sbAlsoGroups, func(id string) bool {
+				return slices.ContainsFunc(groups, func(g Group) bool { return g.ID == id && g.Name == s })
+			})
+			refreshAlsoGroupsButton()
 			autoSave()
 		}
 	}
 
 	btnManageGroups := widget.NewButton("Manage Groups", func() { showGroupManager() })
+	sbColorRect = canvas.NewRectangle(color.Transparent)
+	sbColorRect.SetMinSize(fyne.NewSize(20, 20))
+	sbColorRect.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	sbColorRect.StrokeWidth = 1
+	colorBtn := widget.NewButton("Item Color…", showItemColorPicker)
+	sbAlsoGroupsBtn = widget.NewButtonWithIcon("", theme.ContentAddIcon(), showAlsoGroupsDialog)
+	sbAlsoGroupsBtn.Alignment = widget.ButtonAlignLeading
+	refreshAlsoGroupsButton()
+
+	sbPrioritySelect = widget.NewSelect([]string{string(PriorityLow), string(PriorityMedium), string(PriorityHigh)}, func(s string) { autoSave() })
+	sbPrioritySelect.SetSelected(string(PriorityMedium))
+
+	sbReminderSelect = widget.NewSelect(reminderLabels(), func(s string) { autoSave() })
+	sbReminderSelect.SetSelected("None")
 
 	// Task Inputs
 	lblDeadline := widget.NewLabel("Deadline")
@@ -315,28 +976,69 @@ func createSidebar() fyne.CanvasObject {
 			}
 		}
 		autoSave()
+		updateDurationLabel()
 	}
 
 	btnDateStart, getStartDate, setSDate := createDatePickerButton(mainWindow, onStartChange)
 	setStartDate = setSDate
 	getStartDateVal = getStartDate
 
-	hStart, mStart, apStart, contTimeStart, setSTime := createTimePicker(func() { autoSave() })
+	hStart, mStart, apStart, contTimeStart, setSTime := createTimePicker(func() { autoSave(); updateDurationLabel() })
 	setStartTime = setSTime
 	getStartTimeVal = func() (string, string, string) { return hStart.Selected, mStart.Selected, apStart.Selected }
 
 	lblEnd := widget.NewLabel("End Time")
-	btnDateEnd, getEnd, setE := createDatePickerButton(mainWindow, func(s string) { autoSave() })
+	btnDateEnd, getEnd, setE := createDatePickerButton(mainWindow, func(s string) { autoSave(); updateDurationLabel() })
 	getEndDateStr = getEnd
 	setEndDateStr = setE
 	setEndDate = setE
 	getEndDateVal = getEnd
 
-	hEnd, mEnd, apEnd, contTimeEnd, setETime := createTimePicker(func() { autoSave() })
+	hEnd, mEnd, apEnd, contTimeEnd, setETime := createTimePicker(func() { autoSave(); updateDurationLabel() })
 	setEndTime = setETime
 	getEndTimeVal = func() (string, string, string) { return hEnd.Selected, mEnd.Selected, apEnd.Selected }
 
-	eventContainer := container.NewVBox(lblStart, container.NewGridWithColumns(2, btnDateStart, contTimeStart), lblEnd, container.NewGridWithColumns(2, btnDateEnd, contTimeEnd))
+	sbDurationLabel = widget.NewLabel("")
+	sbDurationLabel.Importance = widget.LowImportance
+	presetRow := container.NewGridWithColumns(4)
+	for _, p := range []struct {
+		label string
+		d     time.Duration
+	}{{"+30m", 30 * time.Minute}, {"+1h", time.Hour}, {"+2h", 2 * time.Hour}} {
+		d := p.d
+		presetRow.Add(widget.NewButton(p.label, func() {
+			hS, mS, apS := getStartTimeVal()
+			start, err := parseItemTime(combineDateTime(getStartDateVal(), hS, mS, apS))
+			if err != nil {
+				return
+			}
+			sbAllDayCheck.SetChecked(false)
+			end := start.Add(d)
+			eh, em, eap := formatTimeParts(end)
+			setEndDate(end.Format("2006-01-02"))
+			setEndTime(eh, em, eap)
+			autoSave()
+			updateDurationLabel()
+		}))
+	}
+	presetRow.Add(widget.NewButton("All day", func() { sbAllDayCheck.SetChecked(true) }))
+
+	sbShowAsSelect = widget.NewSelect([]string{string(AvailabilityBusy), string(AvailabilityFree)}, func(s string) { autoSave() })
+	sbShowAsSelect.SetSelected(string(AvailabilityBusy))
+	eventContainer := container.NewVBox(lblStart, container.NewGridWithColumns(2, btnDateStart, contTimeStart), lblEnd, container.NewGridWithColumns(2, btnDateEnd, contTimeEnd), presetRow, sbDurationLabel,
+		container.NewBorder(nil, nil, widget.NewLabel("Show As"), nil, sbShowAsSelect))
+
+	sbAllDayCheck = widget.NewCheck("All Day", func(b bool) {
+		for _, c := range []*fyne.Container{contTimeDead, contTimeStart, contTimeEnd} {
+			if b {
+				c.Hide()
+			} else {
+				c.Show()
+			}
+		}
+		autoSave()
+		updateDurationLabel()
+	})
 
 	dynamicArea := container.NewVBox()
 
@@ -371,21 +1073,33 @@ func createSidebar() fyne.CanvasObject {
 	recDaySelect = widget.NewSelect([]string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}, nil)
 	recDaySelect.SetSelected("Monday")
 	method2Content := container.NewGridWithColumns(2, recOrdinalSelect, recDaySelect)
-	recModeRadio = widget.NewRadioGroup([]string{"Interval", "Specific Day"}, func(s string) {
-		if s == "Interval" {
+	recNthSelect = widget.NewSelect([]string{"1st", "2nd", "3rd", "4th", "Last"}, nil)
+	recNthSelect.SetSelected("1st")
+	recNthDaySelect = widget.NewSelect([]string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}, nil)
+	recNthDaySelect.SetSelected("Monday")
+	recMonthsEntry = widget.NewEntry()
+	recMonthsEntry.SetText("1")
+	method3Content := container.NewVBox(container.NewGridWithColumns(2, recNthSelect, recNthDaySelect), container.NewBorder(nil, nil, widget.NewLabel("Every"), widget.NewLabel("Month(s)"), recMonthsEntry))
+	recModeRadio = widget.NewRadioGroup([]string{"Interval", "Specific Day", "Monthly Weekday", recModeWeekdays, recModeWeekends}, func(s string) {
+		for _, w := range []fyne.Disableable{recNumEntry, recUnitSelect, recOrdinalSelect, recDaySelect, recNthSelect, recNthDaySelect, recMonthsEntry} {
+			w.Disable()
+		}
+		switch s {
+		case "Interval":
 			recNumEntry.Enable()
 			recUnitSelect.Enable()
-			recOrdinalSelect.Disable()
-			recDaySelect.Disable()
-		} else {
-			recNumEntry.Disable()
-			recUnitSelect.Disable()
+		case "Specific Day":
 			recOrdinalSelect.Enable()
 			recDaySelect.Enable()
+		case "Monthly Weekday":
+			recNthSelect.Enable()
+			recNthDaySelect.Enable()
+			recMonthsEntry.Enable()
 		}
 	})
 	recModeRadio.SetSelected("Interval")
-	recContainer = container.NewVBox(recModeRadio, method1Content, method2Content)
+	recAdvanceCheck = widget.NewCheck("Single item that moves on when completed", nil)
+	recContainer = container.NewVBox(recModeRadio, method1Content, method2Content, method3Content, recAdvanceCheck)
 	recContainer.Hide()
 
 	sbActionBtn = widget.NewButtonWithIcon("Add Item", theme.ContentAddIcon(), func() {
@@ -405,31 +1119,99 @@ func createSidebar() fyne.CanvasObject {
 	sbDeleteBtn.Hide()
 
 	exportBtn := widget.NewButton("Export .ICS", exportICS)
+	exportCSVBtn := widget.NewButton("Export CSV", exportCSV)
+
+	sbTagsEntry = widget.NewEntry()
+	sbTagsEntry.PlaceHolder = "e.g. #urgent, @home"
+	sbTagsEntry.OnChanged = func(s string) { autoSave() }
+
+	sbSubtaskBox = container.NewVBox()
+	subtaskEntry := widget.NewEntry()
+	subtaskEntry.PlaceHolder = "Add checklist item"
+	addSubtask := func() {
+		title := strings.TrimSpace(subtaskEntry.Text)
+		if title == "" {
+			return
+		}
+		sbSubtasks = append(sbSubtasks, Subtask{Title: title})
+		subtaskEntry.SetText("")
+		refreshSubtaskEditor()
+		autoSave()
+	}
+	subtaskEntry.OnSubmitted = func(string) { addSubtask() }
+	subtaskAddBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), addSubtask)
+
+	sbAttachmentBox = container.NewVBox()
+	linkEntry := widget.NewEntry()
+	linkEntry.PlaceHolder = "Paste a link or file path"
+	addAttachment := func(link string) {
+		link = strings.TrimSpace(link)
+		if link == "" || slices.Contains(sbAttachments, link) {
+			return
+		}
+		sbAttachments = append(sbAttachments, link)
+		refreshAttachmentEditor()
+		autoSave()
+	}
+	linkEntry.OnSubmitted = func(s string) { addAttachment(s); linkEntry.SetText("") }
+	linkAddBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() { addAttachment(linkEntry.Text); linkEntry.SetText("") })
+	fileAddBtn := widget.NewButtonWithIcon("Add File…", theme.FolderOpenIcon(), func() {
+		dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			addAttachment(reader.URI().Path())
+		}, mainWindow).Show()
+	})
 
 	topPart := container.NewVBox(
-		sbHeaderLabel,
+		container.NewBorder(nil, nil, nil, sbSavedLabel, sbHeaderLabel),
 		widget.NewLabel("Type"), sbTypeSelect,
 		widget.NewLabel("Title"), sbTitleEntry,
+		sbAllDayCheck,
 		widget.NewLabel("Group"), sbGroupSelect,
+		sbAlsoGroupsBtn,
 		btnManageGroups,
+		container.NewBorder(nil, nil, container.NewCenter(sbColorRect), nil, colorBtn),
+		widget.NewLabel("Priority"), sbPrioritySelect,
+		widget.NewLabel("Reminder"), sbReminderSelect,
+		widget.NewLabel("Tags"), sbTagsEntry,
+		widget.NewLabel("Checklist"), sbSubtaskBox,
+		container.NewBorder(nil, nil, nil, subtaskAddBtn, subtaskEntry),
+		widget.NewLabel("Attachments"), sbAttachmentBox,
+		container.NewBorder(nil, nil, nil, container.NewHBox(linkAddBtn, fileAddBtn), linkEntry),
 	)
 
+	sbConflictLabel = widget.NewLabel("")
+	sbConflictLabel.Importance = widget.DangerImportance
+	sbConflictLabel.Wrapping = fyne.TextWrapWord
+	sbConflictLabel.Hide()
+
 	bottomPart := container.NewVBox(
+		sbConflictLabel,
 		recCheck, recContainer,
 		layout.NewSpacer(),
 		container.NewHBox(sbActionBtn, sbDeleteBtn),
 		sbCancelBtn,
-		exportBtn,
+		container.NewGridWithColumns(2, exportBtn, exportCSVBtn),
 	)
 
-	sbTypeSelect.SetSelected("Task")
+	applySidebarDefaults()
+
+	miniViewDate = time.Now()
+	miniCalendar = container.NewVBox()
+	refreshMiniCalendar()
 
-	return container.NewPadded(container.NewBorder(topPart, bottomPart, nil, nil, dynamicArea))
+	return container.NewPadded(container.NewBorder(topPart, bottomPart, nil, nil, container.NewVBox(dynamicArea, miniCalendar)))
 }
 
 // --- LOGIC: ADD ---
 
 func handleSidebarAction() {
+	if blockReadOnly() {
+		return
+	}
 	if sbTitleEntry.Text == "" {
 		dialog.ShowError(fmt.Errorf("title required"), mainWindow)
 		return
@@ -441,143 +1223,391 @@ func handleSidebarAction() {
 			break
 		}
 	}
-	if selectedGroupID == "" && len(groups) > 0 {
+	inbox := appSettings.InboxEnabled && (sbGroupSelect.Selected == inboxGroupName || sbGroupSelect.Selected == "")
+	if selectedGroupID == "" && len(groups) > 0 && !inbox {
 		selectedGroupID = groups[0].ID
 		sbGroupSelect.SetSelected(groups[0].Name)
 	}
-	if selectedGroupID == "" {
+	if selectedGroupID == "" && !inbox {
 		dialog.ShowError(fmt.Errorf("please select a group"), mainWindow)
 		return
 	}
 
-	combine := func(dateStr, h, m, ap string) string {
-		hour, _ := strconv.Atoi(h)
-		if ap == "PM" && hour != 12 {
-			hour += 12
-		}
-		if ap == "AM" && hour == 12 {
-			hour = 0
-		}
-		return fmt.Sprintf("%s %02d:%s", dateStr, hour, m)
-	}
-
 	var sVal, eVal string
 	curType := TypeTask
 	if sbTypeSelect.Selected == "Event" {
 		curType = TypeEvent
 		hS, mS, apS := getStartTimeVal()
-		sVal = combine(getStartDateVal(), hS, mS, apS)
+		sVal = combineDateTime(getStartDateVal(), hS, mS, apS)
 		hE, mE, apE := getEndTimeVal()
-		eVal = combine(getEndDateVal(), hE, mE, apE)
+		eVal = combineDateTime(getEndDateVal(), hE, mE, apE)
 	} else {
 		hD, mD, apD := getTaskTimeVal()
-		sVal = combine(getTaskDateVal(), hD, mD, apD)
+		sVal = combineDateTime(getTaskDateVal(), hD, mD, apD)
 		eVal = sVal
 	}
+	if sbAllDayCheck.Checked {
+		sVal = sVal[:10] + " 00:00"
+		if curType == TypeEvent {
+			eVal = eVal[:10] + " 23:59"
+		} else {
+			eVal = sVal
+		}
+	}
+	if eVal < sVal {
+		msg := "The event ends before it starts. If it runs past midnight, set the end date to the next day."
+		if sbAllDayCheck.Checked {
+			msg = "The end date is before the start date."
+		}
+		dialog.ShowError(fmt.Errorf("%s", msg), mainWindow)
+		return
+	}
 
 	newSeriesID := ""
-	if recCheck.Checked {
+	if recCheck.Checked && !recAdvanceCheck.Checked {
 		newSeriesID = fmt.Sprintf("s-%d", time.Now().UnixNano())
 	}
 
-	baseStart, _ := time.ParseInLocation("2006-01-02 15:04", sVal, time.Local)
-	baseEnd, _ := time.ParseInLocation("2006-01-02 15:04", eVal, time.Local)
-	duration := baseEnd.Sub(baseStart)
-
 	itemsToCreate := []TodoItem{}
 	baseItem := TodoItem{
-		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
-		Title:     sbTitleEntry.Text,
-		GroupID:   selectedGroupID,
-		Type:      curType,
-		Start:     sVal,
-		End:       eVal,
-		SeriesID:  newSeriesID,
-		Completed: false,
+		ID:              fmt.Sprintf("%d", time.Now().UnixNano()),
+		Title:           sbTitleEntry.Text,
+		GroupID:         selectedGroupID,
+		Type:            curType,
+		Start:           sVal,
+		End:             eVal,
+		SeriesID:        newSeriesID,
+		Completed:       false,
+		Priority:        Priority(sbPrioritySelect.Selected),
+		Availability:    sidebarAvailability(),
+		ColorHex:        sbColorHex,
+		AllDay:          sbAllDayCheck.Checked,
+		ReminderMinutes: reminderMinutesFromLabel(sbReminderSelect.Selected),
+		Subtasks:        slices.Clone(sbSubtasks),
+		Tags:            parseTags(sbTagsEntry.Text),
+		Attachments:     slices.Clone(sbAttachments),
+		CreatedAt:       time.Now().Format("2006-01-02 15:04"),
 	}
-	itemsToCreate = append(itemsToCreate, baseItem)
+	setItemGroups(&baseItem, append([]string{selectedGroupID}, sbAlsoGroups...))
 
 	if recCheck.Checked {
-		limitDate := baseStart.AddDate(1, 0, 0)
-		currentDate := baseStart
-		count := 0
-		for count < 100 {
-			if recModeRadio.Selected == "Interval" {
-				n, _ := strconv.Atoi(recNumEntry.Text)
-				if n < 1 {
-					n = 1
-				}
-				switch recUnitSelect.Selected {
-				case "Day(s)":
-					currentDate = currentDate.AddDate(0, 0, n)
-				case "Week(s)":
-					currentDate = currentDate.AddDate(0, 0, n*7)
-				case "Month(s)":
-					currentDate = currentDate.AddDate(0, n, 0)
-				case "Year(s)":
-					currentDate = currentDate.AddDate(n, 0, 0)
-				}
-			} else {
-				targetDayStr := recDaySelect.Selected
-				targetWeekday := time.Monday
-				switch targetDayStr {
-				case "Sunday":
-					targetWeekday = time.Sunday
-				case "Monday":
-					targetWeekday = time.Monday
-				case "Tuesday":
-					targetWeekday = time.Tuesday
-				case "Wednesday":
-					targetWeekday = time.Wednesday
-				case "Thursday":
-					targetWeekday = time.Thursday
-				case "Friday":
-					targetWeekday = time.Friday
-				case "Saturday":
-					targetWeekday = time.Saturday
-				}
-				daysToAdd := 0
-				for {
-					daysToAdd++
-					d := currentDate.AddDate(0, 0, daysToAdd)
-					if d.Weekday() == targetWeekday {
-						currentDate = d
-						break
-					}
-				}
-				if recOrdinalSelect.Selected == "Every Other" {
-					currentDate = currentDate.AddDate(0, 0, 7)
-				}
+		n, _ := strconv.Atoi(recNumEntry.Text)
+		rule := recurrenceRule{Mode: recModeRadio.Selected, Interval: n, Unit: recUnitSelect.Selected, Ordinal: recOrdinalSelect.Selected, Weekday: recDaySelect.Selected}
+		if rule.Mode == "Monthly Weekday" {
+			rule.Interval, _ = strconv.Atoi(recMonthsEntry.Text)
+			rule.Weekday = recNthDaySelect.Selected
+			rule.Nth = slices.Index([]string{"1st", "2nd", "3rd", "4th"}, recNthSelect.Selected) + 1
+			if rule.Nth == 0 {
+				rule.Nth = -1
 			}
-			if currentDate.After(limitDate) {
-				break
+		}
+		// Start the series on its first matching day rather than on an off-pattern date.
+		s, _ := parseItemTime(baseItem.Start)
+		shift := 0
+		if onDay := presetRecurrenceDays(rule.Mode); onDay != nil {
+			for !onDay(s.AddDate(0, 0, shift).Weekday()) {
+				shift++
 			}
-			newItem := baseItem
-			newItem.ID = fmt.Sprintf("%d-%d", time.Now().UnixNano(), count)
-			newItem.Start = currentDate.Format("2006-01-02 15:04")
-			newItem.End = currentDate.Add(duration).Format("2006-01-02 15:04")
-			itemsToCreate = append(itemsToCreate, newItem)
-			count++
+		} else if rule.Mode == "Monthly Weekday" {
+			monthStart := s.AddDate(0, 0, 1-s.Day())
+			first := nthWeekdayOfMonth(monthStart, parseWeekday(rule.Weekday), rule.Nth)
+			if daysBetween(s, first) < 0 {
+				first = nthWeekdayOfMonth(monthStart.AddDate(0, 1, 0), parseWeekday(rule.Weekday), rule.Nth)
+			}
+			shift = daysBetween(s, first)
+		}
+		if shift != 0 {
+			shiftItemDays(&baseItem, shift)
+		}
+		if recAdvanceCheck.Checked {
+			rule.Anchor = baseItem.Start
+			baseItem.RecurrenceRule = &rule
+			itemsToCreate = append(itemsToCreate, baseItem)
+		} else {
+			itemsToCreate = append(itemsToCreate, baseItem)
+			itemsToCreate = append(itemsToCreate, generateOccurrences(baseItem, rule)...)
 		}
+	} else {
+		itemsToCreate = append(itemsToCreate, baseItem)
 	}
 
+	updateConflictWarning(baseItem)
 	items = append(items, itemsToCreate...)
 	saveData()
 	refreshCalendar()
 	refreshKanban()
 	sbTitleEntry.SetText("")
+	sbSubtasks = nil
+	refreshSubtaskEditor()
+	sbAttachments = nil
+	refreshAttachmentEditor()
 }
 
-// --- STATE MANAGEMENT ---
+const (
+	recModeWeekdays = "Every weekday (Mon–Fri)"
+	recModeWeekends = "Every weekend day (Sat/Sun)"
+)
 
-func updateSidebarHeader() {
-	mode := "Add New"
-	if currentEditItemID != "" {
-		mode = "Edit"
+// presetRecurrenceDays reports which weekdays a preset mode repeats on, or nil for the configurable modes.
+func presetRecurrenceDays(mode string) func(time.Weekday) bool {
+	switch mode {
+	case recModeWeekdays:
+		return func(d time.Weekday) bool { return d != time.Saturday && d != time.Sunday }
+	case recModeWeekends:
+		return func(d time.Weekday) bool { return d == time.Saturday || d == time.Sunday }
 	}
-	itemType := sbTypeSelect.Selected
-	if itemType == "" {
-		itemType = "Item"
+	return nil
+}
+
+type recurrenceRule struct {
+	Mode     string    `json:"mode"`
+	Interval int       `json:"interval,omitempty"`
+	Unit     string    `json:"unit,omitempty"`
+	Ordinal  string    `json:"ordinal,omitempty"`
+	Weekday  string    `json:"weekday,omitempty"`
+	Nth      int       `json:"nth,omitempty"`
+	Until    time.Time `json:"until,omitzero"`
+	Count    int       `json:"count,omitempty"`
+	// Anchor is the first occurrence's start; self-repeating items step from it so month ends don't drift.
+	Anchor string `json:"anchor,omitempty"`
+}
+
+func generateOccurrences(baseItem TodoItem, rule recurrenceRule) []TodoItem {
+	baseStart, _ := parseItemTime(baseItem.Start)
+	baseEnd, _ := parseItemTime(baseItem.End)
+	duration := baseEnd.Sub(baseStart)
+	limitDate := baseStart.AddDate(1, 0, 0)
+	if !rule.Until.IsZero() && rule.Until.Before(limitDate) {
+		limitDate = rule.Until
+	}
+	// Every rule advances by at least a day, so a daily repeat over the one-year window is the most this can produce.
+	maxCount := 366
+	if rule.Count > 0 && rule.Count-1 < maxCount {
+		maxCount = rule.Count - 1
+	}
+	created := []TodoItem{}
+	currentDate := baseStart
+	count := 0
+	for count < maxCount {
+		currentDate = nextOccurrence(baseStart, currentDate, rule, count)
+		if currentDate.After(limitDate) {
+			break
+		}
+		newItem := baseItem
+		newItem.ID = fmt.Sprintf("%d-%d", time.Now().UnixNano(), count)
+		newItem.Subtasks = slices.Clone(baseItem.Subtasks)
+		newItem.Tags = slices.Clone(baseItem.Tags)
+		newItem.Start = currentDate.Format("2006-01-02 15:04")
+		newItem.End = currentDate.Add(duration).Format("2006-01-02 15:04")
+		created = append(created, newItem)
+		count++
+	}
+	return created
+}
+
+// nextOccurrence returns the occurrence after current, the count-th one generated after baseStart. Month and
+// year steps are taken from baseStart so clamped month ends don't drift.
+func nextOccurrence(baseStart, current time.Time, rule recurrenceRule, count int) time.Time {
+	n := max(rule.Interval, 1)
+	targetWeekday := parseWeekday(rule.Weekday)
+	if onDay := presetRecurrenceDays(rule.Mode); onDay != nil {
+		current = current.AddDate(0, 0, 1)
+		for !onDay(current.Weekday()) {
+			current = current.AddDate(0, 0, 1)
+		}
+		return current
+	}
+	switch rule.Mode {
+	case "Monthly Weekday":
+		return nthWeekdayOfMonth(baseStart.AddDate(0, 0, 1-baseStart.Day()).AddDate(0, n*(count+1), 0), targetWeekday, rule.Nth)
+	case "Specific Day":
+		current = current.AddDate(0, 0, 1)
+		for current.Weekday() != targetWeekday {
+			current = current.AddDate(0, 0, 1)
+		}
+		if rule.Ordinal == "Every Other" {
+			current = current.AddDate(0, 0, 7)
+		}
+		return current
+	}
+	switch rule.Unit {
+	case "Day(s)":
+		return current.AddDate(0, 0, n)
+	case "Week(s)":
+		return current.AddDate(0, 0, n*7)
+	case "Month(s)":
+		return addMonthsClamped(baseStart, n*(count+1))
+	case "Year(s)":
+		return addMonthsClamped(baseStart, 12*n*(count+1))
+	}
+	return current
+}
+
+// addMonthsClamped steps from the series start rather than the previous occurrence, pinning the day to the
+// month's last day when it's shorter, so "the 31st" gives Feb 28/29 and then Mar 31 again instead of drifting.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), 0, 0, t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+func parseWeekday(name string) time.Weekday {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == name {
+			return d
+		}
+	}
+	return time.Monday
+}
+
+// nth is 1-4, or -1 for the last such weekday; month carries the time of day.
+func nthWeekdayOfMonth(month time.Time, wd time.Weekday, nth int) time.Time {
+	first := time.Date(month.Year(), month.Month(), 1, month.Hour(), month.Minute(), 0, 0, time.Local)
+	if nth < 0 {
+		last := first.AddDate(0, 1, -1)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(nth-1))
+}
+
+// icsWeekdays holds the RRULE BYDAY codes, indexed by time.Weekday.
+var icsWeekdays = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// parseRRule maps an RRULE onto the app's modes; BYDAY is understood for the weekday/weekend presets and
+// for "nth weekday" monthly rules. BYMONTHDAY/BYSETPOS are left to the clamped month stepping.
+func parseRRule(value string) (recurrenceRule, bool) {
+	rule := recurrenceRule{Mode: "Interval", Interval: 1}
+	var byDay []string
+	for _, part := range strings.Split(value, ";") {
+		k, v, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			switch strings.ToUpper(v) {
+			case "DAILY":
+				rule.Unit = "Day(s)"
+			case "WEEKLY":
+				rule.Unit = "Week(s)"
+			case "MONTHLY":
+				rule.Unit = "Month(s)"
+			case "YEARLY":
+				rule.Unit = "Year(s)"
+			}
+		case "INTERVAL":
+			rule.Interval, _ = strconv.Atoi(v)
+		case "COUNT":
+			rule.Count, _ = strconv.Atoi(v)
+		case "BYDAY":
+			byDay = strings.Split(strings.ToUpper(v), ",")
+		case "UNTIL":
+			if t, err := time.Parse("20060102T150405Z", v); err == nil {
+				rule.Until = t.Local()
+			} else if t, err := time.ParseInLocation("20060102T150405", v, time.Local); err == nil {
+				rule.Until = t
+			} else if t, err := time.ParseInLocation("20060102", v, time.Local); err == nil {
+				rule.Until = t.Add(24*time.Hour - time.Minute)
+			}
+		}
+	}
+	slices.Sort(byDay)
+	switch {
+	case rule.Unit == "Week(s)" && slices.Equal(byDay, []string{"FR", "MO", "TH", "TU", "WE"}):
+		rule.Mode = recModeWeekdays
+	case rule.Unit == "Week(s)" && slices.Equal(byDay, []string{"SA", "SU"}):
+		rule.Mode = recModeWeekends
+	case rule.Unit == "Month(s)" && len(byDay) == 1 && len(byDay[0]) > 2:
+		code := byDay[0][len(byDay[0])-2:]
+		nth, err := strconv.Atoi(strings.TrimPrefix(byDay[0][:len(byDay[0])-2], "+"))
+		if wd := slices.Index(icsWeekdays[:], code); err == nil && wd >= 0 && (nth == -1 || (nth >= 1 && nth <= 4)) {
+			rule.Mode, rule.Nth, rule.Weekday = "Monthly Weekday", nth, time.Weekday(wd).String()
+		}
+	}
+	return rule, rule.Unit != ""
+}
+
+// --- STATE MANAGEMENT ---
+
+func refreshWeekdayHeader() {
+	if weekdayHeaderGrid == nil {
+		return
+	}
+	weekdayHeaderGrid.Objects = nil
+	for _, d := range weekdayHeaders(false) {
+		weekdayHeaderGrid.Add(widget.NewLabelWithStyle(d, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	weekdayHeaderGrid.Refresh()
+}
+
+func refreshMiniCalendar() {
+	if miniCalendar == nil {
+		return
+	}
+	btnPrev := widget.NewButton("<", func() { miniViewDate = miniViewDate.AddDate(0, -1, 0); refreshMiniCalendar() })
+	btnNext := widget.NewButton(">", func() { miniViewDate = miniViewDate.AddDate(0, 1, 0); refreshMiniCalendar() })
+	btnPrev.Importance = widget.LowImportance
+	btnNext.Importance = widget.LowImportance
+	header := container.NewBorder(nil, nil, btnPrev, btnNext, widget.NewLabelWithStyle(formatDate(miniViewDate, "January 2006"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	grid := container.NewGridWithColumns(7)
+	for _, day := range weekdayHeaders(true) {
+		lbl := canvas.NewText(day, theme.Color(theme.ColorNamePlaceHolder))
+		lbl.TextSize = scaledText(10)
+		lbl.Alignment = fyne.TextAlignCenter
+		grid.Add(lbl)
+	}
+	y, m, _ := miniViewDate.Date()
+	first := time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+	off := int(first.Weekday())
+	if off == 0 {
+		off = 7
+	}
+	off--
+	for i := 0; i < off; i++ {
+		grid.Add(layout.NewSpacer())
+	}
+	now := time.Now()
+	dim := first.AddDate(0, 1, -1).Day()
+	for i := 1; i <= dim; i++ {
+		day := time.Date(y, m, i, 0, 0, 0, 0, time.Local)
+		dayBtn := widget.NewButton(strconv.Itoa(i), func() { retargetSidebarDate(day) })
+		dayBtn.Importance = widget.LowImportance
+		if y == now.Year() && m == now.Month() && i == now.Day() {
+			dayBtn.Importance = widget.MediumImportance
+		}
+		if y == selectedCalendarDate.Year() && m == selectedCalendarDate.Month() && i == selectedCalendarDate.Day() {
+			dayBtn.Importance = widget.HighImportance
+		}
+		grid.Add(dayBtn)
+	}
+	miniCalendar.Objects = []fyne.CanvasObject{widget.NewSeparator(), header, grid}
+	miniCalendar.Refresh()
+}
+
+func retargetSidebarDate(day time.Time) {
+	dateStr := day.Format("2006-01-02")
+	setTaskDate(dateStr)
+	if s, err := time.Parse("2006-01-02", getStartDateVal()); err == nil {
+		if e, err := time.Parse("2006-01-02", getEndDateVal()); err == nil && !e.Before(s) {
+			setEndDate(day.AddDate(0, 0, int(e.Sub(s).Hours()/24)).Format("2006-01-02"))
+		} else {
+			setEndDate(dateStr)
+		}
+	}
+	setStartDate(dateStr)
+	selectedCalendarDate = day
+	currentViewDate = day
+	autoSave()
+	refreshCalendar()
+}
+
+func updateSidebarHeader() {
+	mode := "Add New"
+	if currentEditItemID != "" {
+		mode = "Edit"
+	}
+	itemType := sbTypeSelect.Selected
+	if itemType == "" {
+		itemType = "Item"
 	}
 	sbHeaderLabel.SetText(fmt.Sprintf("%s %s", mode, itemType))
 	if sbActionBtn != nil {
@@ -590,7 +1620,53 @@ func updateSidebarHeader() {
 }
 
 func startEditing(item *TodoItem) {
+	if blockReadOnly() {
+		return
+	}
+	id := item.ID
+	confirmDiscardSidebar(func() {
+		for i := range items {
+			if items[i].ID == id {
+				loadSidebarItem(&items[i])
+				return
+			}
+		}
+	})
+}
+
+// sidebarDirty reports edits that would be lost: an unsubmitted new item, or an existing one whose title was cleared (autoSave skips those).
+func sidebarDirty() (bool, string) {
+	if currentEditItemID == "" {
+		if sbTitleEntry.Text != "" || len(sbSubtasks) > 0 || sbTagsEntry.Text != "" || len(sbAttachments) > 0 {
+			return true, "You have a new item that hasn't been added yet. Discard it?"
+		}
+		return false, ""
+	}
+	if sbTitleEntry.Text == "" {
+		return true, "The title is empty, so the current edit can't be saved. Discard it?"
+	}
+	return false, ""
+}
+func confirmDiscardSidebar(proceed func()) {
+	dirty, msg := sidebarDirty()
+	if !dirty {
+		proceed()
+		return
+	}
+	dialog.ShowConfirm("Discard Changes", msg, func(ok bool) {
+		if ok {
+			sbTitleEntry.SetText("")
+			sbSubtasks = nil
+			sbAttachments = nil
+			proceed()
+		}
+	}, mainWindow)
+}
+func loadSidebarItem(item *TodoItem) {
+	finishSeriesEdit()
+	sidebarLoading = true
 	currentEditItemID = item.ID
+	editOriginal = *item
 	sbCancelBtn.Show()
 	sbDeleteBtn.Show()
 	sbActionBtn.Hide()
@@ -601,71 +1677,441 @@ func startEditing(item *TodoItem) {
 			break
 		}
 	}
+	if item.GroupID == "" {
+		sbGroupSelect.SetSelected(inboxGroupName)
+	}
+	sbAlsoGroups = itemGroupIDs(item)[1:]
+	refreshAlsoGroupsButton()
 	sbTypeSelect.SetSelected(string(item.Type))
-	s, _ := time.ParseInLocation("2006-01-02 15:04", item.Start, time.Local)
-	e, _ := time.ParseInLocation("2006-01-02 15:04", item.End, time.Local)
-	getTimeParts := func(t time.Time) (string, string, string) {
-		h := t.Hour()
-		ap := "AM"
-		if h >= 12 {
-			ap = "PM"
-			if h > 12 {
-				h -= 12
-			}
-		}
-		if h == 0 {
-			h = 12
-		}
-		return fmt.Sprintf("%02d", h), fmt.Sprintf("%02d", t.Minute()), ap
+	if item.Priority == "" {
+		sbPrioritySelect.SetSelected(string(PriorityMedium))
+	} else {
+		sbPrioritySelect.SetSelected(string(item.Priority))
+	}
+	setSidebarColor(item.ColorHex)
+	if item.Availability == AvailabilityFree {
+		sbShowAsSelect.SetSelected(string(AvailabilityFree))
+	} else {
+		sbShowAsSelect.SetSelected(string(AvailabilityBusy))
 	}
-	h, m, ap := getTimeParts(s)
+	sbReminderSelect.SetSelected(reminderLabel(item.ReminderMinutes))
+	sbSubtasks = slices.Clone(item.Subtasks)
+	sbTagsEntry.SetText(strings.Join(item.Tags, ", "))
+	refreshSubtaskEditor()
+	sbAttachments = slices.Clone(item.Attachments)
+	refreshAttachmentEditor()
+	s, errS := parseItemTime(item.Start)
+	e, errE := parseItemTime(item.End)
+	if errS != nil || errE != nil {
+		dialog.ShowError(fmt.Errorf("'%s' has an unreadable date (%s); pick a new one before saving", item.Title, item.Start), mainWindow)
+		s, e = selectedCalendarDate, selectedCalendarDate
+	}
+	h, m, ap := formatTimeParts(s)
 	if item.Type == TypeTask {
 		setTaskDate(s.Format("2006-01-02"))
 		setTaskTime(h, m, ap)
 	} else {
 		setStartDate(s.Format("2006-01-02"))
 		setStartTime(h, m, ap)
-		eh, em, eap := getTimeParts(e)
+		eh, em, eap := formatTimeParts(e)
 		setEndDate(e.Format("2006-01-02"))
 		setEndTime(eh, em, eap)
 	}
+	sbAllDayCheck.SetChecked(item.AllDay)
 	recCheck.SetChecked(false)
 	recContainer.Hide()
+	sidebarLoading = false
 	updateSidebarHeader()
+	updateConflictWarning(*item)
+	updateDurationLabel()
+}
+
+// correctEventEnd keeps an edited event from ending before it starts. Moving the start past the end carries the old duration
+// along; picking an earlier clock time on the same day is read as running past midnight.
+func correctEventEnd(prevStart, prevEnd, start, end string, allDay bool) string {
+	if end >= start {
+		return end
+	}
+	s, err1 := parseItemTime(start)
+	e, err2 := parseItemTime(end)
+	if err1 != nil || err2 != nil {
+		return end
+	}
+	if allDay {
+		return start[:10] + " 23:59"
+	}
+	if start == prevStart && s.Format("2006-01-02") == e.Format("2006-01-02") {
+		return e.AddDate(0, 0, 1).Format("2006-01-02 15:04")
+	}
+	duration := time.Hour
+	if ps, err := parseItemTime(prevStart); err == nil {
+		if pe, err := parseItemTime(prevEnd); err == nil && !pe.Before(ps) {
+			duration = pe.Sub(ps)
+		}
+	}
+	return s.Add(duration).Format("2006-01-02 15:04")
+}
+func updateDurationLabel() {
+	if sbDurationLabel == nil || sbAllDayCheck == nil || getStartTimeVal == nil || getEndTimeVal == nil {
+		return
+	}
+	if sbAllDayCheck.Checked {
+		sbDurationLabel.SetText("Duration: all day")
+		return
+	}
+	hS, mS, apS := getStartTimeVal()
+	hE, mE, apE := getEndTimeVal()
+	start, err1 := parseItemTime(combineDateTime(getStartDateVal(), hS, mS, apS))
+	end, err2 := parseItemTime(combineDateTime(getEndDateVal(), hE, mE, apE))
+	if err1 != nil || err2 != nil {
+		sbDurationLabel.SetText("")
+		return
+	}
+	sbDurationLabel.SetText("Duration: " + formatDuration(end.Sub(start)))
+}
+
+func refreshSubtaskEditor() {
+	sbSubtaskBox.Objects = nil
+	for i := range sbSubtasks {
+		idx := i
+		check := widget.NewCheck(sbSubtasks[idx].Title, func(b bool) {
+			sbSubtasks[idx].Done = b
+			autoSave()
+		})
+		check.Checked = sbSubtasks[idx].Done
+		removeBtn := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+			sbSubtasks = slices.Delete(sbSubtasks, idx, idx+1)
+			refreshSubtaskEditor()
+			autoSave()
+		})
+		sbSubtaskBox.Add(container.NewBorder(nil, nil, nil, removeBtn, check))
+	}
+	sbSubtaskBox.Refresh()
+}
+func refreshAttachmentEditor() {
+	sbAttachmentBox.Objects = nil
+	for i := range sbAttachments {
+		idx := i
+		link := widget.NewButtonWithIcon(attachmentName(sbAttachments[idx]), theme.MailAttachmentIcon(), func() { openAttachment(sbAttachments[idx]) })
+		link.Alignment = widget.ButtonAlignLeading
+		link.Importance = widget.LowImportance
+		removeBtn := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+			sbAttachments = slices.Delete(sbAttachments, idx, idx+1)
+			refreshAttachmentEditor()
+			autoSave()
+		})
+		sbAttachmentBox.Add(container.NewBorder(nil, nil, nil, removeBtn, link))
+	}
+	sbAttachmentBox.Refresh()
+}
+
+// attachmentURL treats anything with a scheme as a URL and everything else as a local file path.
+func attachmentURL(link string) (*url.URL, error) {
+	if u, err := url.Parse(link); err == nil && len(u.Scheme) > 1 {
+		return u, nil
+	}
+	return url.Parse(storage.NewFileURI(link).String())
+}
+func attachmentName(link string) string {
+	if u, err := url.Parse(link); err == nil && len(u.Scheme) > 1 {
+		return link
+	}
+	return filepath.Base(link)
+}
+func openAttachment(link string) {
+	u, err := attachmentURL(link)
+	if err == nil {
+		err = fyne.CurrentApp().OpenURL(u)
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("could not open %s: %v", link, err), mainWindow)
+	}
+}
+
+// showAttachmentMenu opens a lone attachment directly, or lists them when there are several.
+func showAttachmentMenu(item *TodoItem, anchor fyne.CanvasObject) {
+	if len(item.Attachments) == 1 {
+		openAttachment(item.Attachments[0])
+		return
+	}
+	menuItems := []*fyne.MenuItem{}
+	for _, link := range item.Attachments {
+		link := link
+		menuItems = append(menuItems, fyne.NewMenuItem(attachmentName(link), func() { openAttachment(link) }))
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(anchor).Add(fyne.NewPos(0, anchor.Size().Height))
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("Attachments", menuItems...), mainWindow.Canvas(), pos)
+}
+func allSubtasksDone(subtasks []Subtask) bool {
+	if len(subtasks) == 0 {
+		return false
+	}
+	for _, st := range subtasks {
+		if !st.Done {
+			return false
+		}
+	}
+	return true
+}
+
+func finishSeriesEdit() {
+	if currentEditItemID == "" || editOriginal.SeriesID == "" {
+		return
+	}
+	var edited *TodoItem
+	for i := range items {
+		if items[i].ID == currentEditItemID {
+			edited = &items[i]
+			break
+		}
+	}
+	if edited == nil {
+		return
+	}
+	original := editOriginal
+	changed := *edited
+	groupsChanged := !slices.Equal(itemGroupIDs(&changed), itemGroupIDs(&original))
+	isFirst := !slices.ContainsFunc(items, func(it TodoItem) bool {
+		return it.SeriesID == original.SeriesID && it.ID != original.ID && it.Start < original.Start
+	})
+	if changed.Start != original.Start && isFirst {
+		// Rescheduling the first occurrence usually means moving the meeting, so offer to carry the series along.
+		oldStart, _ := parseItemTime(original.Start)
+		newStart, _ := parseItemTime(changed.Start)
+		msg := fmt.Sprintf("You moved the first '%s' to %s.\nMove the rest of the series by the same amount?", changed.Title, formatDate(newStart, "Mon, Jan 2 ")+formatClock(newStart))
+		dialog.ShowConfirm("Move Whole Series", msg, func(ok bool) {
+			if ok {
+				shiftSeries(original.SeriesID, original.ID, oldStart, newStart)
+				saveData()
+				refreshCalendar()
+				refreshKanban()
+			}
+			applySeriesFieldEdits(original, changed, groupsChanged)
+		}, mainWindow)
+		return
+	}
+	applySeriesFieldEdits(original, changed, groupsChanged)
+}
+
+// shiftSeries moves every occurrence but skipID, and the series' skipped dates, by the step from oldStart to newStart.
+// Days and clock time are shifted separately so occurrences keep their wall-clock time across DST changes.
+func shiftSeries(seriesID, skipID string, oldStart, newStart time.Time) {
+	days := daysBetween(oldStart, newStart)
+	clock := time.Duration(minutesIntoDay(newStart)-minutesIntoDay(oldStart)) * time.Minute
+	shift := func(v string) string {
+		t, err := parseItemTime(v)
+		if err != nil {
+			return v
+		}
+		return t.AddDate(0, 0, days).Add(clock).Format("2006-01-02 15:04")
+	}
+	for i := range items {
+		if items[i].SeriesID != seriesID {
+			continue
+		}
+		if items[i].ID != skipID {
+			items[i].Start, items[i].End = shift(items[i].Start), shift(items[i].End)
+		}
+		exceptions := slices.Clone(items[i].Exceptions)
+		for j, ex := range exceptions {
+			exceptions[j] = shift(ex)
+		}
+		items[i].Exceptions = exceptions
+	}
+}
+
+// applySeriesFieldEdits offers to copy a sidebar edit of the title, groups or priority to the rest of the series.
+func applySeriesFieldEdits(original, changed TodoItem, groupsChanged bool) {
+	if changed.Title == original.Title && !groupsChanged && changed.Priority == original.Priority {
+		return
+	}
+	i := slices.IndexFunc(items, func(it TodoItem) bool { return it.ID == changed.ID })
+	if i < 0 {
+		return
+	}
+	chooseSeriesScope(&items[i], "Edit Recurring", "Repeating item. Apply changes to?", func(targets []*TodoItem) {
+		for _, t := range targets {
+			if changed.Title != original.Title {
+				t.Title = changed.Title
+			}
+			if groupsChanged {
+				setItemGroups(t, itemGroupIDs(&changed))
+			}
+			if changed.Priority != original.Priority {
+				t.Priority = changed.Priority
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	})
 }
 
 func resetSidebar() {
+	finishSeriesEdit()
 	currentEditItemID = ""
 	sbCancelBtn.Hide()
 	sbDeleteBtn.Hide()
 	sbActionBtn.Show()
 	sbTitleEntry.SetText("")
+	sbPrioritySelect.SetSelected(string(PriorityMedium))
+	sbShowAsSelect.SetSelected(string(AvailabilityBusy))
+	setSidebarColor("")
+	sbReminderSelect.SetSelected("None")
+	sbSubtasks = nil
+	refreshSubtaskEditor()
+	sbAttachments = nil
+	refreshAttachmentEditor()
+	sbAlsoGroups = nil
+	refreshAlsoGroupsButton()
+	sbTagsEntry.SetText("")
+	sbAllDayCheck.SetChecked(false)
+	sbConflictLabel.Hide()
 	recCheck.SetChecked(false)
+	recAdvanceCheck.SetChecked(false)
 	recContainer.Hide()
+	applySidebarDefaults()
 	updateSidebarHeader()
 }
 
+// applySidebarDefaults fills the new-item form from the "New Items" settings. The default group is stored by
+// name, so it applies in every calendar that has a group called that.
+func applySidebarDefaults() {
+	sbTypeSelect.SetSelected(defaultItemType())
+	if slices.ContainsFunc(groups, func(g Group) bool { return g.Name == appSettings.DefaultGroup }) {
+		sbGroupSelect.SetSelected(appSettings.DefaultGroup)
+	}
+	day := selectedCalendarDate
+	start := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+	if t, err := time.Parse("15:04", appSettings.DefaultStart); err == nil {
+		start = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+	}
+	end := start.Add(time.Duration(defaultDurationMinutes()) * time.Minute)
+	if end.Day() != start.Day() {
+		end = time.Date(start.Year(), start.Month(), start.Day(), 23, 59, 0, 0, time.Local)
+	}
+	if setTaskTime != nil {
+		setTaskTime(formatTimeParts(start))
+		setStartTime(formatTimeParts(start))
+		setEndTime(formatTimeParts(end))
+	}
+	updateDurationLabel()
+}
+func defaultItemType() string {
+	if appSettings.DefaultType == string(TypeEvent) {
+		return string(TypeEvent)
+	}
+	return string(TypeTask)
+}
+func defaultDurationMinutes() int {
+	if appSettings.DefaultDuration <= 0 {
+		return 60
+	}
+	return appSettings.DefaultDuration
+}
+
+// workingMinutes returns the configured working hours as minutes into the day, 09:00–17:00 unless set.
+func workingMinutes() (from, to float32) {
+	from, to = 9*60, 17*60
+	if t, err := time.Parse("15:04", appSettings.WorkStart); err == nil {
+		from = float32(t.Hour()*60 + t.Minute())
+	}
+	if t, err := time.Parse("15:04", appSettings.WorkEnd); err == nil {
+		to = float32(t.Hour()*60 + t.Minute())
+	}
+	if to <= from {
+		return 9 * 60, 17 * 60
+	}
+	return from, to
+}
+func isWeekend(day time.Time) bool {
+	return slices.Contains(appSettings.WeekendDays, int(day.Weekday()))
+}
+
+var offHoursColor = color.RGBA{128, 128, 128, 30}
+
 // --- CALENDAR VIEW ---
 
 func createCalendarArea() fyne.CanvasObject {
-	btnPrev := widget.NewButton("<", func() { currentViewDate = currentViewDate.AddDate(0, -1, 0); refreshCalendar() })
-	btnNext := widget.NewButton(">", func() { currentViewDate = currentViewDate.AddDate(0, 1, 0); refreshCalendar() })
+	step := func(dir int) {
+		switch calendarMode {
+		case "Week":
+			currentViewDate = currentViewDate.AddDate(0, 0, 7*dir)
+		case "Day":
+			currentViewDate = currentViewDate.AddDate(0, 0, dir)
+		default:
+			currentViewDate = currentViewDate.AddDate(0, dir, 0)
+		}
+		refreshCalendar()
+	}
+	btnPrev := widget.NewButton("<", func() { step(-1) })
+	btnNext := widget.NewButton(">", func() { step(1) })
+	btnToday := widget.NewButton("Today", func() {
+		currentViewDate = time.Now()
+		selectedCalendarDate = time.Now()
+		refreshCalendar()
+	})
 	monthLabel = widget.NewLabel("")
 	monthLabel.TextStyle = fyne.TextStyle{Bold: true}
 	monthLabel.Alignment = fyne.TextAlignCenter
-	nav := container.NewBorder(nil, nil, btnPrev, btnNext, monthLabel)
-	headerGrid := container.NewGridWithColumns(7)
-	for _, d := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
-		headerGrid.Add(widget.NewLabelWithStyle(d, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	modeRadio := widget.NewRadioGroup([]string{"Month", "Week", "Day"}, func(s string) {
+		if s == "" || s == calendarMode {
+			return
+		}
+		calendarMode = s
+		appSettings.ViewMode = s
+		saveSettings()
+		refreshCalendar()
+		if s != "Month" {
+			weekScroll.ScrollToOffset(fyne.NewPos(0, float32(max(time.Now().Hour()-2, 0))*weekHourHeight))
+		}
+	})
+	modeRadio.Horizontal = true
+	modeRadio.Required = true
+	if slices.Contains(modeRadio.Options, appSettings.ViewMode) {
+		calendarMode = appSettings.ViewMode
 	}
+	modeRadio.SetSelected(calendarMode)
+	jumpToDate := newClickableBox(container.NewStack(monthLabel), func() {
+		showDatePicker(mainWindow, currentViewDate, func(t time.Time) {
+			currentViewDate = t
+			selectedCalendarDate = t
+			refreshCalendar()
+		})
+	})
+	nav := container.NewBorder(nil, nil, container.NewHBox(btnPrev, btnToday), container.NewHBox(modeRadio, btnNext), jumpToDate)
+	weekdayHeaderGrid = container.NewGridWithColumns(7)
+	refreshWeekdayHeader()
 	calendarGrid = container.NewGridWithColumns(7)
+	monthView = container.NewBorder(weekdayHeaderGrid, nil, nil, nil, calendarGrid)
+	gutter := canvas.NewRectangle(color.Transparent)
+	gutter.SetMinSize(fyne.NewSize(weekGutter, 1))
+	weekHeader = container.NewGridWithColumns(7)
+	weekBody = container.New(&timeGridLayout{cols: 7})
+	weekScroll = container.NewVScroll(weekBody)
+	weekView = container.NewBorder(container.NewBorder(nil, nil, gutter, nil, weekHeader), nil, nil, nil, weekScroll)
 	refreshCalendar()
-	return container.NewBorder(container.NewVBox(nav, headerGrid), nil, nil, nil, calendarGrid)
+	return container.NewBorder(nav, nil, nil, nil, container.NewStack(monthView, weekView))
 }
 
 func refreshCalendar() {
-	monthLabel.SetText(currentViewDate.Format("January 2006"))
+	hideTooltip()
+	if !miniSyncedDate.Equal(selectedCalendarDate) {
+		miniSyncedDate = selectedCalendarDate
+		miniViewDate = selectedCalendarDate
+	}
+	refreshMiniCalendar()
+	if calendarMode != "Month" {
+		monthView.Hide()
+		weekView.Show()
+		refreshTimeView()
+		return
+	}
+	weekView.Hide()
+	monthView.Show()
+	monthLabel.SetText(formatDate(currentViewDate, "January 2006"))
 	calendarGrid.Objects = nil
+	calendarDropTargets = nil
 	groupColorMap := make(map[string]color.Color)
 	for _, g := range groups {
 		groupColorMap[g.ID] = parseHexColor(g.ColorHex)
@@ -685,749 +2131,5654 @@ func refreshCalendar() {
 		dayStart := time.Date(year, month, d, 0, 0, 0, 0, time.Local)
 		dayEnd := time.Date(year, month, d, 23, 59, 59, 0, time.Local)
 		bgCell := canvas.NewRectangle(color.Transparent)
+		if isWeekend(dayStart) {
+			bgCell.FillColor = offHoursColor
+		}
 		if dayStart.Year() == selectedCalendarDate.Year() && dayStart.Month() == selectedCalendarDate.Month() && dayStart.Day() == selectedCalendarDate.Day() {
 			bgCell.FillColor = color.RGBA{80, 80, 80, 80}
 			bgCell.StrokeColor = theme.PrimaryColor()
 			bgCell.StrokeWidth = 2
 		}
 		cellContent := container.NewVBox(widget.NewLabelWithStyle(strconv.Itoa(d), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
-		for i := range items {
-			item := &items[i]
-			s, _ := time.ParseInLocation("2006-01-02 15:04", item.Start, time.Local)
-			e, _ := time.ParseInLocation("2006-01-02 15:04", item.End, time.Local)
-			if s.Before(dayEnd) && (e.After(dayStart) || e.Equal(dayStart)) {
-				c, exists := groupColorMap[item.GroupID]
-				if !exists {
-					c = color.Gray{Y: 100}
+		dayItems := itemsOnDay(dayStart, dayEnd)
+		var dayBlocks []fyne.CanvasObject
+		for _, item := range dayItems {
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			c := itemColor(item, groupColorMap)
+			if item.Completed {
+				c = dimColor(c)
+			}
+			var displayBlock *fyne.Container
+			timeStr := formatClock(s)
+			if item.Type == TypeEvent {
+				timeStr = fmt.Sprintf("%s - %s", formatClock(s), formatClock(e))
+			}
+			overdue := isOverdue(item)
+			title := seriesMark(item) + item.Title
+			if item.Calendar != "" {
+				title = calendarLabel(item.Calendar) + ": " + title
+			}
+			if span := daysBetween(s, lastShownDay(s, e)) + 1; item.Type == TypeEvent && span > 1 {
+				part := daysBetween(s, dayStart) + 1
+				title = fmt.Sprintf("%s (%d/%d)", title, part, span)
+				if part > 1 {
+					title = "◀ " + title
 				}
-				if item.Completed {
-					c = color.RGBA{200, 200, 200, 255}
+				if part < span {
+					title += " ▶"
 				}
-				var displayBlock *fyne.Container
-				timeStr := s.Format("15:04")
-				if item.Type == TypeEvent {
-					timeStr = fmt.Sprintf("%s - %s", s.Format("15:04"), e.Format("15:04"))
+				switch part {
+				case 1:
+					timeStr = "from " + formatClock(s)
+				case span:
+					timeStr = "until " + formatClock(e)
+				default:
+					timeStr = "all day"
 				}
-				if item.Type == TypeTask {
-					displayText := fmt.Sprintf("• %s %s", timeStr, item.Title)
-					if item.Completed {
-						displayBlock = container.NewPadded(createStrikethroughText(displayText, c, 10))
-					} else {
-						displayBlock = container.NewPadded(canvas.NewText(displayText, c))
-					}
+			}
+			if item.AllDay {
+				bg := canvas.NewRectangle(c)
+				bg.SetMinSize(fyne.NewSize(10, 16))
+				if item.Completed {
+					displayBlock = container.NewStack(bg, container.NewPadded(createStrikethroughText(title, color.White, scaledText(10))))
 				} else {
-					bg := canvas.NewRectangle(c)
-					bg.SetMinSize(fyne.NewSize(10, 16))
-					eventText := fmt.Sprintf("%s (%s)", item.Title, timeStr)
-					if item.Completed {
-						displayBlock = container.NewStack(bg, container.NewPadded(createStrikethroughText(eventText, color.White, 10)))
-					} else {
-						displayBlock = container.NewStack(bg, container.NewPadded(canvas.NewText(eventText, color.White)))
+					text := canvas.NewText(title, color.White)
+					text.TextSize = scaledText(10)
+					if overdue {
+						bg.StrokeColor = overdueColor
+						bg.StrokeWidth = 2
+						text.Text = "! " + title
 					}
+					displayBlock = container.NewStack(bg, container.NewPadded(text))
 				}
-				clickable := newClickableBox(displayBlock, func() { startEditing(item) })
-				clickable.onRight = func(e *fyne.PointEvent) {
-					statusLabel := "Mark Complete"
-					if item.Completed {
-						statusLabel = "Mark Incomplete"
+			} else if item.Type == TypeTask {
+				displayText := fmt.Sprintf("• %s %s", timeStr, title)
+				if item.Completed {
+					displayBlock = container.NewPadded(createStrikethroughText(displayText, c, scaledText(10)))
+				} else {
+					text := canvas.NewText(displayText, c)
+					text.TextSize = scaledText(10)
+					if overdue {
+						text.Text, text.Color = fmt.Sprintf("! %s %s", timeStr, title), overdueColor
 					}
-					widget.ShowPopUpMenuAtPosition(fyne.NewMenu("Actions", fyne.NewMenuItem(statusLabel, func() { item.Completed = !item.Completed; saveData(); refreshCalendar(); refreshKanban() }), fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Move to...", func() { showMoveDialog(item) }), fyne.NewMenuItem("Delete", func() { performSmartDelete(item.ID) })), mainWindow.Canvas(), e.AbsolutePosition)
+					displayBlock = container.NewPadded(text)
+				}
+			} else {
+				bg := canvas.NewRectangle(c)
+				bg.SetMinSize(fyne.NewSize(10, 16))
+				eventText := fmt.Sprintf("%s (%s)", title, timeStr)
+				if item.Completed {
+					displayBlock = container.NewStack(bg, container.NewPadded(createStrikethroughText(eventText, color.White, scaledText(10))))
+				} else {
+					text := canvas.NewText(eventText, color.White)
+					text.TextSize = scaledText(10)
+					displayBlock = container.NewStack(bg, container.NewPadded(text))
 				}
-				cellContent.Add(clickable)
-			}
-		}
-		clickDateStr := dayStart.Format("2006-01-02")
-		interactiveCell := newClickableBox(cellContent, func() {
-			resetSidebar()
-			selectedCalendarDate = dayStart
-			if setTaskDate != nil {
-				setTaskDate(clickDateStr)
 			}
-			if setStartDate != nil {
-				setStartDate(clickDateStr)
+			clickable := newDraggableBox(displayBlock, func() { startEditing(item) })
+			clickable.onRight = func(e *fyne.PointEvent) { showItemActions(item, e.AbsolutePosition) }
+			clickable.tooltip = itemTooltip(item)
+			sourceDay := dayStart
+			var dropTarget *calendarDropTarget
+			clickable.onDrag = func(e *fyne.DragEvent) {
+				var hovered *calendarDropTarget
+				for i := range calendarDropTargets {
+					if objectContains(calendarDropTargets[i].area, e.AbsolutePosition) {
+						hovered = &calendarDropTargets[i]
+						break
+					}
+				}
+				if hovered == dropTarget {
+					return
+				}
+				if dropTarget != nil {
+					dropTarget.highlight.StrokeWidth = 0
+					dropTarget.highlight.Refresh()
+				}
+				dropTarget = hovered
+				if dropTarget != nil && !dropTarget.day.Equal(sourceDay) {
+					dropTarget.highlight.StrokeColor = theme.PrimaryColor()
+					dropTarget.highlight.StrokeWidth = 3
+					dropTarget.highlight.Refresh()
+				}
 			}
-			if setEndDate != nil {
-				setEndDate(clickDateStr)
+			clickable.onDragEnd = func() {
+				if dropTarget == nil {
+					return
+				}
+				target := dropTarget
+				dropTarget = nil
+				target.highlight.StrokeWidth = 0
+				target.highlight.Refresh()
+				days := daysBetween(sourceDay, target.day)
+				if days == 0 || blockReadOnly() {
+					return
+				}
+				chooseSeriesScope(item, "Move Recurring", "Repeating item. Move?", func(targets []*TodoItem) {
+					for _, t := range targets {
+						shiftItemDays(t, days)
+					}
+					syncSidebarDates()
+					selectedCalendarDate = target.day
+					saveData()
+					refreshCalendar()
+					refreshKanban()
+				})
 			}
-			refreshCalendar()
-		})
-		calendarGrid.Add(widget.NewCard("", "", container.NewStack(bgCell, interactiveCell)))
-	}
-}
-
-// --- KANBAN VIEW ---
+			dayBlocks = append(dayBlocks, clickable)
+		}
+		limit := appSettings.MaxItemsPerCell
+		if limit < 1 || limit > len(dayBlocks) {
+			limit = len(dayBlocks)
+		}
+		cellContent.Objects = append(cellContent.Objects, dayBlocks[:limit]...)
+		if hidden := len(dayBlocks) - limit; hidden > 0 {
+			cellDate := dayStart
+			moreText := canvas.NewText(fmt.Sprintf("+%d more", hidden), theme.PrimaryColor())
+			moreText.TextSize = scaledText(10)
+			cellContent.Add(newClickableBox(container.NewPadded(moreText), func() { showDayItemsDialog(cellDate, dayItems) }))
+		}
+		interactiveCell := newClickableBox(cellContent, func() { selectCalendarDay(dayStart) })
+		interactiveCell.onRight = func(e *fyne.PointEvent) {
+			menu := fyne.NewMenu("", fyne.NewMenuItem("Copy Day as Text", func() { copyDayAgenda(dayStart, dayItems) }))
+			widget.ShowPopUpMenuAtPosition(menu, mainWindow.Canvas(), e.AbsolutePosition)
+		}
+		dropHighlight := canvas.NewRectangle(color.Transparent)
+		cell := widget.NewCard("", "", container.NewStack(bgCell, interactiveCell, dropHighlight))
+		calendarDropTargets = append(calendarDropTargets, calendarDropTarget{day: dayStart, area: cell, highlight: dropHighlight})
+		calendarGrid.Add(cell)
+	}
+}
 
-func createKanbanArea() fyne.CanvasObject {
-	kanbanContainer = container.NewHBox()
-	return container.NewHScroll(container.NewPadded(kanbanContainer))
+// copyDayAgenda puts a day's items on the clipboard as "09:00 Standup" lines, all-day items first.
+func copyDayAgenda(day time.Time, dayItems []*TodoItem) {
+	if len(dayItems) == 0 {
+		dialog.ShowInformation("Copy Day", "There are no items on "+formatDate(day, "Monday, January 2")+".", mainWindow)
+		return
+	}
+	timed := slices.Clone(dayItems)
+	slices.SortStableFunc(timed, func(a, b *TodoItem) int {
+		if a.AllDay != b.AllDay {
+			if a.AllDay {
+				return -1
+			}
+			return 1
+		}
+		as, _ := parseItemTime(a.Start)
+		bs, _ := parseItemTime(b.Start)
+		return as.Compare(bs)
+	})
+	var lines []string
+	for _, item := range timed {
+		when := "All day"
+		if s, _ := parseItemTime(item.Start); !item.AllDay {
+			if s.Before(day) {
+				s = day
+			}
+			when = formatClock(s)
+		}
+		line := when + " " + item.Title
+		if item.Calendar != "" {
+			line = when + " " + item.Calendar + ": " + item.Title
+		}
+		if item.Completed {
+			line += " (done)"
+		}
+		lines = append(lines, line)
+	}
+	mainWindow.Clipboard().SetContent(strings.Join(lines, "\n"))
 }
-func refreshKanban() {
-	kanbanContainer.Objects = nil
-	itemsByGroup := make(map[string][]*TodoItem)
+
+// lastShownDay treats an end as exclusive, so an event running until exactly midnight isn't drawn on the next day.
+// Zero-length items (tasks) keep their own time.
+func lastShownDay(s, e time.Time) time.Time {
+	if e.After(s) && e.Hour() == 0 && e.Minute() == 0 {
+		return e.Add(-time.Minute)
+	}
+	return e
+}
+
+// itemsOnDay returns the visible items overlapping a day, all-day ones first, in items order otherwise.
+func itemsOnDay(dayStart, dayEnd time.Time) []*TodoItem {
+	var allDay, timed []*TodoItem
 	for i := range items {
-		itemsByGroup[items[i].GroupID] = append(itemsByGroup[items[i].GroupID], &items[i])
+		item := &items[i]
+		if !isVisible(item) {
+			continue
+		}
+		s, _ := parseItemTime(item.Start)
+		e, _ := parseItemTime(item.End)
+		if s.Before(dayEnd) && !lastShownDay(s, e).Before(dayStart) {
+			if item.AllDay {
+				allDay = append(allDay, item)
+			} else {
+				timed = append(timed, item)
+			}
+		}
 	}
-	for i := range groups {
-		grp := &groups[i]
-		grpColor := parseHexColor(grp.ColorHex)
-		headerLabel := canvas.NewText(grp.Name, color.White)
-		headerLabel.TextStyle = fyne.TextStyle{Bold: true}
-		sortBtn := widget.NewButtonWithIcon("", theme.MenuIcon(), func() {
-			widget.ShowPopUpMenuAtPosition(fyne.NewMenu("Sort", fyne.NewMenuItem("Sort by Date", func() { grp.SortMode = "date"; saveGroups(); refreshKanban() }), fyne.NewMenuItem("Sort A-Z", func() { grp.SortMode = "alpha"; saveGroups(); refreshKanban() })), mainWindow.Canvas(), fyne.CurrentApp().Driver().AbsolutePositionForObject(headerLabel))
-		})
-		headerBg := canvas.NewRectangle(grpColor)
-		headerBg.SetMinSize(fyne.NewSize(250, 40))
-		headerContent := container.NewBorder(nil, nil, nil, sortBtn, container.NewCenter(headerLabel))
-		itemsBox := container.NewVBox()
-		grpItems := itemsByGroup[grp.ID]
-		sort.Slice(grpItems, func(a, b int) bool {
-			if grpItems[a].Completed != grpItems[b].Completed {
-				return !grpItems[a].Completed
+	return append(allDay, timed...)
+}
+func selectCalendarDay(day time.Time) {
+	confirmDiscardSidebar(func() { applyCalendarDay(day) })
+}
+func applyCalendarDay(day time.Time) {
+	resetSidebar()
+	selectedCalendarDate = day
+	clickDateStr := day.Format("2006-01-02")
+	if setTaskDate != nil {
+		setTaskDate(clickDateStr)
+	}
+	if setStartDate != nil {
+		setStartDate(clickDateStr)
+	}
+	if setEndDate != nil {
+		setEndDate(clickDateStr)
+	}
+	refreshCalendar()
+}
+
+// --- WEEK / DAY TIME VIEW ---
+
+const weekHourHeight float32 = 40
+const weekGutter float32 = 48
+
+// timeSlot places one object of the time grid; col is a day column, or slotFull/slotGutter.
+type timeSlot struct {
+	col            int
+	fromMin, toMin float32
+	lane, lanes    int
+	thin           bool
+}
+
+const (
+	slotFull   = -1
+	slotGutter = -2
+)
+
+type timeGridLayout struct {
+	cols  int
+	slots []timeSlot
+}
+
+func (l *timeGridLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	colW := (size.Width - weekGutter) / float32(l.cols)
+	for i, o := range objects {
+		s := l.slots[i]
+		y := s.fromMin / 60 * weekHourHeight
+		h := (s.toMin - s.fromMin) / 60 * weekHourHeight
+		if h < 1 {
+			h = max(o.MinSize().Height, 1)
+		}
+		x, w := weekGutter+colW*float32(s.col), colW
+		switch {
+		case s.col == slotFull:
+			x, w = 0, size.Width
+		case s.col == slotGutter:
+			x, w = 0, weekGutter
+		case s.thin:
+			w = 1
+		case s.lanes > 1:
+			w = colW / float32(s.lanes)
+			x += w * float32(s.lane)
+		}
+		if s.lanes > 0 {
+			x, w = x+1, w-2
+		}
+		o.Move(fyne.NewPos(x, y))
+		o.Resize(fyne.NewSize(w, h))
+	}
+}
+func (l *timeGridLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(weekGutter+float32(l.cols)*60, 24*weekHourHeight)
+}
+
+// resizeEventEnd moves an event's end by a dragged pixel distance in the time grid, snapped to the minute step
+// and kept within the day it is shown on.
+func resizeEventEnd(item *TodoItem, dy float32, day time.Time) {
+	refreshCalendar()
+	if blockReadOnly() {
+		return
+	}
+	s, _ := parseItemTime(item.Start)
+	e, _ := parseItemTime(item.End)
+	step := max(appSettings.MinuteStep, 1)
+	mins := int(math.Round(float64(dy/weekHourHeight*60/float32(step)))) * step
+	newEnd := e.Add(time.Duration(mins) * time.Minute).Truncate(time.Minute)
+	newEnd = newEnd.Add(-time.Duration(newEnd.Minute()%step) * time.Minute)
+	if minEnd := s.Add(time.Duration(step) * time.Minute); newEnd.Before(minEnd) {
+		newEnd = minEnd
+	}
+	if limit := day.Add(24*time.Hour - time.Minute); newEnd.After(limit) {
+		newEnd = limit
+	}
+	if newEnd.Equal(e) {
+		return
+	}
+	duration := newEnd.Sub(s)
+	chooseSeriesScope(item, "Resize Recurring", "Repeating event. Change the duration of:", func(targets []*TodoItem) {
+		for _, t := range targets {
+			ts, _ := parseItemTime(t.Start)
+			t.End = ts.Add(duration).Format("2006-01-02 15:04")
+		}
+		if currentEditItemID == item.ID {
+			loadSidebarItem(item)
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	})
+}
+func weekStartOf(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+func minutesIntoDay(t time.Time) float32 {
+	return float32(t.Hour()*60 + t.Minute())
+}
+
+// timeSlotTarget is the empty background of a day column; tapping it starts a new item at that time.
+type timeSlotTarget struct {
+	widget.BaseWidget
+	day time.Time
+}
+
+func newTimeSlotTarget(day time.Time) *timeSlotTarget {
+	t := &timeSlotTarget{day: day}
+	t.ExtendBaseWidget(t)
+	return t
+}
+func (t *timeSlotTarget) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+func (t *timeSlotTarget) Tapped(e *fyne.PointEvent) {
+	step := max(appSettings.MinuteStep, 1)
+	mins := int(math.Round(float64(e.Position.Y/weekHourHeight*60/float32(step)))) * step
+	selectCalendarSlot(time.Date(t.day.Year(), t.day.Month(), t.day.Day(), 0, min(mins, 1440-step), 0, 0, time.Local))
+}
+
+// selectCalendarSlot is selectCalendarDay with the start time filled in as well, and the end at the default length.
+func selectCalendarSlot(start time.Time) {
+	confirmDiscardSidebar(func() {
+		applyCalendarDay(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local))
+		sbAllDayCheck.SetChecked(false)
+		h, m, ap := formatTimeParts(start)
+		setTaskTime(h, m, ap)
+		setStartTime(h, m, ap)
+		end := start.Add(time.Duration(defaultDurationMinutes()) * time.Minute)
+		eh, em, eap := formatTimeParts(end)
+		setEndDate(end.Format("2006-01-02"))
+		setEndTime(eh, em, eap)
+		updateDurationLabel()
+	})
+}
+func refreshTimeView() {
+	first := time.Date(currentViewDate.Year(), currentViewDate.Month(), currentViewDate.Day(), 0, 0, 0, 0, time.Local)
+	cols := 1
+	if calendarMode == "Week" {
+		first = weekStartOf(currentViewDate)
+		cols = 7
+		last := first.AddDate(0, 0, 6)
+		monthLabel.SetText(fmt.Sprintf("%s – %s", formatDate(first, "Jan 2"), formatDate(last, "Jan 2, 2006")))
+	} else {
+		monthLabel.SetText(formatDate(first, "Monday, January 2 2006"))
+	}
+	groupColorMap := make(map[string]color.Color)
+	for _, g := range groups {
+		groupColorMap[g.ID] = parseHexColor(g.ColorHex)
+	}
+	grid := &timeGridLayout{cols: cols}
+	objs := []fyne.CanvasObject{}
+	add := func(o fyne.CanvasObject, s timeSlot) {
+		objs = append(objs, o)
+		grid.slots = append(grid.slots, s)
+	}
+	weekHeader.Objects = nil
+	weekHeader.Layout = layout.NewGridLayoutWithColumns(cols)
+	now := time.Now()
+	weekNowLine = nil
+	weekNowDay = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for d := 0; d < cols; d++ {
+		day := first.AddDate(0, 0, d)
+		if day.Equal(weekNowDay) {
+			todayBg := canvas.NewRectangle(color.RGBA{52, 152, 219, 25})
+			add(todayBg, timeSlot{col: d, fromMin: 0, toMin: 1440})
+		}
+		if isWeekend(day) {
+			add(canvas.NewRectangle(offHoursColor), timeSlot{col: d, fromMin: 0, toMin: 1440})
+		} else {
+			workFrom, workTo := workingMinutes()
+			add(canvas.NewRectangle(offHoursColor), timeSlot{col: d, fromMin: 0, toMin: workFrom})
+			add(canvas.NewRectangle(offHoursColor), timeSlot{col: d, fromMin: workTo, toMin: 1440})
+		}
+		add(canvas.NewRectangle(color.RGBA{128, 128, 128, 80}), timeSlot{col: d, fromMin: 0, toMin: 1440, thin: true})
+		add(newTimeSlotTarget(day), timeSlot{col: d, fromMin: 0, toMin: 1440})
+		headerStyle := fyne.TextStyle{Bold: day.Equal(weekNowDay)}
+		dayHeader := container.NewVBox(newClickableBox(container.NewPadded(widget.NewLabelWithStyle(formatDate(day, "Mon 2"), fyne.TextAlignCenter, headerStyle)), func() { selectCalendarDay(day) }))
+		freeText := canvas.NewText(freeTimeLabel(day), color.Gray{Y: 140})
+		freeText.TextSize = scaledText(10)
+		freeText.Alignment = fyne.TextAlignCenter
+		dayHeader.Add(freeText)
+		dayEnd := day.Add(24*time.Hour - time.Minute)
+		var timed []*TodoItem
+		for i := range items {
+			item := &items[i]
+			if !isVisible(item) {
+				continue
 			}
-			if grp.SortMode == "alpha" {
-				return strings.ToLower(grpItems[a].Title) < strings.ToLower(grpItems[b].Title)
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			if s.After(dayEnd) || lastShownDay(s, e).Before(day) {
+				continue
 			}
-			return grpItems[a].Start < grpItems[b].Start
-		})
-		for _, item := range grpItems {
-			cardBgColor := color.Color(color.RGBA{240, 240, 240, 255})
-			textColor := color.Color(color.Black)
+			c := itemColor(item, groupColorMap)
 			if item.Completed {
-				cardBgColor = color.RGBA{220, 220, 220, 255}
-				textColor = color.RGBA{150, 150, 150, 255}
+				c = dimColor(c)
 			}
-			cardBg := canvas.NewRectangle(cardBgColor)
-			cardBg.StrokeColor = color.RGBA{200, 200, 200, 255}
-			cardBg.StrokeWidth = 1
-			cardBg.CornerRadius = 5
-			var titleObj fyne.CanvasObject
-			if item.Completed {
-				titleObj = createStrikethroughText(item.Title, textColor, 12)
+			if item.AllDay {
+				bg := canvas.NewRectangle(c)
+				label := widget.NewLabel(item.Title)
+				label.Truncation = fyne.TextTruncateEllipsis
+				bar := newClickableBox(container.NewStack(bg, label), func() { startEditing(item) })
+				bar.onRight = func(e *fyne.PointEvent) { showItemActions(item, e.AbsolutePosition) }
+				dayHeader.Add(bar)
+				continue
+			}
+			timed = append(timed, item)
+		}
+		weekHeader.Add(dayHeader)
+		sort.Slice(timed, func(a, b int) bool { return timed[a].Start < timed[b].Start })
+		type placed struct {
+			item     *TodoItem
+			from, to float32
+			lane     int
+		}
+		var blocks []placed
+		var laneEnds []float32
+		for _, item := range timed {
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			from, to := float32(0), float32(1440)
+			if !s.Before(day) {
+				from = minutesIntoDay(s)
+			}
+			if !e.After(dayEnd) {
+				to = minutesIntoDay(e)
+			}
+			to = max(to, from+30)
+			lane := 0
+			for lane < len(laneEnds) && laneEnds[lane] > from {
+				lane++
+			}
+			if lane == len(laneEnds) {
+				laneEnds = append(laneEnds, to)
 			} else {
-				t := canvas.NewText(item.Title, textColor)
-				t.TextSize = 12
-				titleObj = t
+				laneEnds[lane] = to
 			}
-			s, _ := time.ParseInLocation("2006-01-02 15:04", item.Start, time.Local)
-			e, _ := time.ParseInLocation("2006-01-02 15:04", item.End, time.Local)
-			dateStr := s.Format("Mon, Jan 02")
-			timeInfo := s.Format("15:04")
-			if item.Type == TypeEvent {
-				timeInfo = fmt.Sprintf("%s - %s", s.Format("15:04"), e.Format("15:04"))
+			blocks = append(blocks, placed{item, from, to, lane})
+		}
+		for _, b := range blocks {
+			item := b.item
+			c := itemColor(item, groupColorMap)
+			if item.Completed {
+				c = dimColor(c)
+			}
+			s, _ := parseItemTime(item.Start)
+			bg := canvas.NewRectangle(c)
+			bg.CornerRadius = 3
+			if item.Type == TypeTask {
+				bg.FillColor = dimColor(c)
+				bg.StrokeColor = c
+				bg.StrokeWidth = 1
+			}
+			if isOverdue(item) {
+				bg.StrokeColor = overdueColor
+				bg.StrokeWidth = 2
+			}
+			label := widget.NewLabel(fmt.Sprintf("%s %s%s", formatClock(s), seriesMark(item), item.Title))
+			label.Truncation = fyne.TextTruncateEllipsis
+			label.Wrapping = fyne.TextWrapOff
+			blockContent := container.NewStack(bg, label)
+			block := newClickableBox(blockContent, func() { startEditing(item) })
+			block.onRight = func(e *fyne.PointEvent) { showItemActions(item, e.AbsolutePosition) }
+			block.tooltip = itemTooltip(item)
+			if e, _ := parseItemTime(item.End); item.Type == TypeEvent && !e.After(dayEnd) && activeCalendarName != allCalendarsName {
+				grip := canvas.NewRectangle(color.Transparent)
+				grip.SetMinSize(fyne.NewSize(0, 6))
+				handle := newResizeHandle(container.NewStack(grip))
+				var dragged float32
+				handle.onDrag = func(ev *fyne.DragEvent) {
+					dragged += ev.Dragged.DY
+					block.Resize(fyne.NewSize(block.Size().Width, max(block.Size().Height+ev.Dragged.DY, 8)))
+				}
+				handle.onDragEnd = func() {
+					delta := dragged
+					dragged = 0
+					resizeEventEnd(item, delta, day)
+				}
+				blockContent.Add(container.NewBorder(nil, handle, nil, nil))
+			}
+			add(block, timeSlot{col: d, fromMin: b.from, toMin: b.to, lane: b.lane, lanes: len(laneEnds)})
+		}
+		if day.Equal(weekNowDay) {
+			weekNowLine = canvas.NewRectangle(overdueColor)
+			weekNowLine.SetMinSize(fyne.NewSize(0, 2))
+			add(weekNowLine, timeSlot{col: d, fromMin: minutesIntoDay(now), toMin: minutesIntoDay(now)})
+		}
+	}
+	for h := 0; h < 24; h++ {
+		add(canvas.NewRectangle(color.RGBA{128, 128, 128, 60}), timeSlot{col: slotFull, fromMin: float32(h * 60), toMin: float32(h * 60)})
+		hourText := canvas.NewText(formatClock(time.Date(2000, 1, 1, h, 0, 0, 0, time.Local)), color.Gray{Y: 140})
+		hourText.TextSize = scaledText(10)
+		add(hourText, timeSlot{col: slotGutter, fromMin: float32(h * 60), toMin: float32(h * 60)})
+	}
+	// Lines and labels sit underneath the item blocks so they don't swallow taps.
+	ordered := make([]fyne.CanvasObject, 0, len(objs))
+	orderedSlots := make([]timeSlot, 0, len(objs))
+	for pass := 0; pass < 2; pass++ {
+		for i, o := range objs {
+			if (grid.slots[i].lanes > 0) == (pass == 1) {
+				ordered = append(ordered, o)
+				orderedSlots = append(orderedSlots, grid.slots[i])
 			}
-			dateLabel := canvas.NewText(fmt.Sprintf("%s | %s", dateStr, timeInfo), color.RGBA{100, 100, 100, 255})
-			dateLabel.TextSize = 10
-			check := widget.NewCheck("", func(b bool) { item.Completed = b; saveData(); refreshCalendar(); refreshKanban() })
+		}
+	}
+	grid.slots = orderedSlots
+	weekBody.Layout = grid
+	weekBody.Objects = ordered
+	weekBody.Refresh()
+	weekHeader.Refresh()
+}
+
+// updateNowLine moves the current-time marker without rebuilding the view, unless the day has rolled over.
+func updateNowLine() {
+	if calendarMode == "Month" {
+		return
+	}
+	now := time.Now()
+	if !weekNowDay.Equal(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)) {
+		refreshCalendar()
+		return
+	}
+	grid, ok := weekBody.Layout.(*timeGridLayout)
+	if weekNowLine == nil || !ok {
+		return
+	}
+	for i, o := range weekBody.Objects {
+		if o == weekNowLine {
+			grid.slots[i].fromMin = minutesIntoDay(now)
+			grid.slots[i].toMin = grid.slots[i].fromMin
+		}
+	}
+	weekBody.Refresh()
+}
+func showDayItemsDialog(day time.Time, dayItems []*TodoItem) {
+	var d dialog.Dialog
+	list := container.NewVBox()
+	for _, item := range dayItems {
+		it := item
+		s, _ := parseItemTime(it.Start)
+		label := fmt.Sprintf("%s  %s", formatClock(s), it.Title)
+		if it.AllDay {
+			label = fmt.Sprintf("All day  %s", it.Title)
+		}
+		list.Add(widget.NewButton(label, func() { d.Hide(); startEditing(it) }))
+	}
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(300, 300))
+	d = dialog.NewCustom(formatDate(day, "Monday, January 2"), "Close", scroll, mainWindow)
+	d.Show()
+}
+
+// --- KANBAN VIEW ---
+
+// --- AGENDA ---
+
+func createAgendaArea() fyne.CanvasObject {
+	agendaContainer = container.NewVBox()
+	return container.NewVScroll(container.NewPadded(agendaContainer))
+}
+
+// refreshAgenda lists the next seven days' visible items by day, with checkboxes to tick them off.
+func refreshAgenda() {
+	if agendaContainer == nil {
+		return
+	}
+	agendaContainer.Objects = nil
+	groupColorMap := make(map[string]color.Color)
+	for _, g := range groups {
+		groupColorMap[g.ID] = parseHexColor(g.ColorHex)
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for d := 0; d < 7; d++ {
+		day := today.AddDate(0, 0, d)
+		heading := formatDate(day, "Monday, Jan 2")
+		switch d {
+		case 0:
+			heading = "Today · " + heading
+		case 1:
+			heading = "Tomorrow · " + heading
+		}
+		freeLabel := widget.NewLabel(freeTimeLabel(day))
+		freeLabel.Importance = widget.LowImportance
+		agendaContainer.Add(container.NewBorder(nil, nil, nil, freeLabel, widget.NewLabelWithStyle(heading, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})))
+		dayItems := itemsOnDay(day, time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, time.Local))
+		if len(dayItems) == 0 {
+			empty := widget.NewLabel("Nothing scheduled")
+			empty.Importance = widget.LowImportance
+			agendaContainer.Add(empty)
+		}
+		for _, item := range dayItems {
+			check := widget.NewCheck("", func(b bool) { setCompleted(item, b); saveData(); refreshCalendar(); refreshKanban() })
 			check.Checked = item.Completed
-			content := container.NewBorder(nil, nil, check, nil, container.NewVBox(titleObj, dateLabel))
-			clickCard := newClickableBox(container.NewStack(cardBg, container.NewPadded(content)), func() { startEditing(item) })
-			clickCard.onRight = func(e *fyne.PointEvent) {
-				sl := "Mark Complete"
-				if item.Completed {
-					sl = "Mark Incomplete"
+			if activeCalendarName == allCalendarsName {
+				check.Disable()
+			}
+			c := itemColor(item, groupColorMap)
+			swatch := canvas.NewRectangle(c)
+			swatch.SetMinSize(fyne.NewSize(6, 0))
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			when := formatClock(s)
+			switch {
+			case item.AllDay:
+				when = "All day"
+			case item.Type == TypeEvent:
+				when = formatClock(s) + " – " + formatClock(e)
+			}
+			text := fmt.Sprintf("%s  %s%s", when, seriesMark(item), item.Title)
+			var label fyne.CanvasObject
+			if item.Completed {
+				label = createStrikethroughText(text, theme.Color(theme.ColorNamePlaceHolder), scaledText(13))
+			} else {
+				l := widget.NewLabel(text)
+				l.Truncation = fyne.TextTruncateEllipsis
+				if isOverdue(item) {
+					l.Importance = widget.DangerImportance
 				}
-				widget.ShowPopUpMenuAtPosition(fyne.NewMenu("Actions", fyne.NewMenuItem(sl, func() { item.Completed = !item.Completed; saveData(); refreshCalendar(); refreshKanban() }), fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Move to...", func() { showMoveDialog(item) }), fyne.NewMenuItem("Delete", func() { performSmartDelete(item.ID) })), mainWindow.Canvas(), e.AbsolutePosition)
+				label = l
 			}
-			itemsBox.Add(clickCard)
+			row := newClickableBox(container.NewBorder(nil, nil, container.NewHBox(swatch, check), nil, label), func() { startEditing(item) })
+			row.onRight = func(e *fyne.PointEvent) { showItemActions(item, e.AbsolutePosition) }
+			row.tooltip = itemTooltip(item)
+			agendaContainer.Add(row)
 		}
-		kanbanContainer.Add(container.NewBorder(container.NewStack(headerBg, headerContent), nil, nil, nil, container.NewVScroll(container.NewPadded(itemsBox))))
-		kanbanContainer.Add(layout.NewSpacer())
+		agendaContainer.Add(widget.NewSeparator())
+	}
+	agendaContainer.Refresh()
+}
+
+// --- STATS ---
+
+func createStatsArea() fyne.CanvasObject {
+	periodRadio := widget.NewRadioGroup([]string{"This Week", "This Month"}, func(s string) {
+		statsPeriod = s
+		refreshStats()
+	})
+	periodRadio.Horizontal = true
+	periodRadio.Required = true
+	periodRadio.SetSelected(statsPeriod)
+	statsContainer = container.NewVBox()
+	return container.NewBorder(container.NewPadded(periodRadio), nil, nil, nil, container.NewVScroll(container.NewPadded(statsContainer)))
+}
+func refreshStats() {
+	now := time.Now()
+	from := weekStartOf(now)
+	to := from.AddDate(0, 0, 7)
+	if statsPeriod == "This Month" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		to = from.AddDate(0, 1, 0)
+	}
+	type groupStats struct {
+		done, pending, finished int
+		booked                  time.Duration
+	}
+	stats := make(map[string]*groupStats)
+	var maxBooked time.Duration
+	for _, item := range items {
+		if item.Archived || invalidDateIDs[item.ID] {
+			continue
+		}
+		// An item in several groups counts towards each of them.
+		for _, groupID := range itemGroupIDs(&item) {
+			st := stats[groupID]
+			if st == nil {
+				st = &groupStats{}
+				stats[groupID] = st
+			}
+			if c, err := parseItemTime(item.CompletedAt); err == nil && item.Completed && !c.Before(from) && c.Before(to) {
+				st.finished++
+			}
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			if !s.Before(to) || e.Before(from) {
+				continue
+			}
+			if item.Completed {
+				st.done++
+			} else {
+				st.pending++
+			}
+			if item.Type == TypeEvent && !item.AllDay {
+				if s.Before(from) {
+					s = from
+				}
+				if e.After(to) {
+					e = to
+				}
+				st.booked += e.Sub(s)
+				maxBooked = max(maxBooked, st.booked)
+			}
+		}
+	}
+	statsContainer.Objects = nil
+	statsContainer.Add(widget.NewLabelWithStyle(fmt.Sprintf("%s – %s", formatDate(from, "Jan 2"), formatDate(to.AddDate(0, 0, -1), "Jan 2, 2006")), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, g := range groups {
+		st := stats[g.ID]
+		if st == nil {
+			st = &groupStats{}
+		}
+		var fraction float32
+		if maxBooked > 0 {
+			fraction = float32(st.booked) / float32(maxBooked)
+		}
+		bar := container.New(&progressLayout{fraction: fraction}, canvas.NewRectangle(color.RGBA{128, 128, 128, 40}), canvas.NewRectangle(parseHexColor(g.ColorHex)))
+		barRow := container.NewGridWrap(fyne.NewSize(300, 14), bar)
+		summary := widget.NewLabel(fmt.Sprintf("%d completed, %d pending, %s booked; %d finished during this period", st.done, st.pending, formatDuration(st.booked), st.finished))
+		statsContainer.Add(widget.NewLabelWithStyle(g.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		statsContainer.Add(summary)
+		statsContainer.Add(barRow)
+		statsContainer.Add(widget.NewSeparator())
+	}
+	statsContainer.Refresh()
+}
+func formatDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %02dm", h, m)
+}
+
+func createKanbanArea() fyne.CanvasObject {
+	kanbanContainer = container.NewHBox()
+	compactCheck := widget.NewCheck("Compact cards", func(b bool) {
+		if b != appSettings.CompactCards {
+			appSettings.CompactCards = b
+			saveSettings()
+			refreshKanban()
+		}
+	})
+	compactCheck.SetChecked(appSettings.CompactCards)
+	clearBtn := widget.NewButtonWithIcon("Clear Completed", theme.ContentClearIcon(), func() {
+		targets := []*TodoItem{}
+		for i := range items {
+			if !items[i].Archived {
+				targets = append(targets, &items[i])
+			}
+		}
+		clearCompleted("this calendar", targets)
+	})
+	selectionLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	selectionBar = container.NewHBox(selectionLabel,
+		widget.NewButtonWithIcon("Complete", theme.ConfirmIcon(), func() { completeSelected() }),
+		widget.NewButtonWithIcon("Move to…", theme.MailForwardIcon(), func() { moveSelected() }),
+		widget.NewButtonWithIcon("Tag…", theme.ContentAddIcon(), func() { tagSelected() }),
+		widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() { deleteSelected() }),
+		widget.NewButtonWithIcon("", theme.CancelIcon(), clearItemSelection),
+	)
+	selectionBar.Hide()
+	top := container.NewVBox(container.NewHBox(clearBtn, layout.NewSpacer(), compactCheck), selectionBar)
+	return container.NewBorder(top, nil, nil, nil, container.NewHScroll(container.NewPadded(kanbanContainer)))
+}
+
+// --- MULTI-SELECTION ---
+
+func selectedItems() []*TodoItem {
+	sel := []*TodoItem{}
+	for i := range items {
+		if selectedItemIDs[items[i].ID] {
+			sel = append(sel, &items[i])
+		}
+	}
+	return sel
+}
+func refreshSelectionBar() {
+	if selectionBar == nil {
+		return
+	}
+	if len(selectedItemIDs) == 0 {
+		selectionBar.Hide()
+		return
+	}
+	selectionLabel.SetText(fmt.Sprintf("%d selected", len(selectedItemIDs)))
+	selectionBar.Show()
+}
+func clearItemSelection() {
+	if len(selectedItemIDs) == 0 {
+		return
+	}
+	clear(selectedItemIDs)
+	for _, k := range kanbanCards {
+		k.updateRing()
+	}
+	refreshSelectionBar()
+}
+func completeSelected() {
+	if blockReadOnly() {
+		return
+	}
+	for _, it := range selectedItems() {
+		setCompleted(it, true)
+	}
+	clear(selectedItemIDs)
+	saveData()
+	refreshCalendar()
+	refreshKanban()
+}
+func moveSelected() {
+	if blockReadOnly() {
+		return
+	}
+	names := []string{}
+	for _, i := range columnOrder() {
+		names = append(names, groups[i].Name)
+	}
+	var d dialog.Dialog
+	sel := widget.NewSelect(names, nil)
+	sel.PlaceHolder = "Select Group"
+	btn := widget.NewButton("Move", func() {
+		i := slices.IndexFunc(groups, func(g Group) bool { return g.Name == sel.Selected })
+		if i < 0 {
+			return
+		}
+		for _, it := range selectedItems() {
+			if !slices.Contains(itemGroupIDs(it), groups[i].ID) {
+				replaceItemGroup(it, it.GroupID, groups[i].ID)
+			}
+		}
+		clear(selectedItemIDs)
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+		d.Hide()
+	})
+	d = dialog.NewCustom(fmt.Sprintf("Move %d Items", len(selectedItemIDs)), "Cancel", container.NewVBox(sel, btn), mainWindow)
+	d.Show()
+}
+func tagSelected() {
+	if blockReadOnly() {
+		return
+	}
+	entry := widget.NewEntry()
+	entry.PlaceHolder = "tag, another tag"
+	dialog.ShowCustomConfirm(fmt.Sprintf("Tag %d Items", len(selectedItemIDs)), "Add Tags", "Cancel", entry, func(ok bool) {
+		tags := parseTags(entry.Text)
+		if !ok || len(tags) == 0 {
+			return
+		}
+		for _, it := range selectedItems() {
+			for _, t := range tags {
+				if !slices.Contains(it.Tags, t) {
+					it.Tags = append(it.Tags, t)
+				}
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+func deleteSelected() {
+	if blockReadOnly() {
+		return
+	}
+	ids := maps.Clone(selectedItemIDs)
+	dialog.ShowConfirm("Delete Items", fmt.Sprintf("Delete %d selected item(s)? This can't be undone.", len(ids)), func(ok bool) {
+		if !ok {
+			return
+		}
+		if ids[currentEditItemID] {
+			resetSidebar()
+		}
+		items = slices.DeleteFunc(items, func(it TodoItem) bool { return ids[it.ID] })
+		clear(selectedItemIDs)
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+func refreshKanban() {
+	hideTooltip()
+	refreshTagFilter()
+	kanbanContainer.Objects = nil
+	kanbanDropTargets = nil
+	kanbanCards = nil
+	visibleIDs := make(map[string]bool)
+	for i := range items {
+		if isVisible(&items[i]) {
+			visibleIDs[items[i].ID] = true
+		}
+	}
+	maps.DeleteFunc(selectedItemIDs, func(id string, _ bool) bool { return !visibleIDs[id] })
+	refreshSelectionBar()
+	itemsByGroup := make(map[string][]*TodoItem)
+	for i := range items {
+		if !isVisible(&items[i]) {
+			continue
+		}
+		for _, id := range itemGroupIDs(&items[i]) {
+			itemsByGroup[id] = append(itemsByGroup[id], &items[i])
+		}
+	}
+	columns := []*Group{}
+	if appSettings.InboxEnabled {
+		columns = append(columns, &inboxGroup)
+	}
+	for _, i := range columnOrder() {
+		columns = append(columns, &groups[i])
+	}
+	for _, grp := range columns {
+		if isGroupHidden(grp.ID) {
+			continue
+		}
+		grpColor := parseHexColor(grp.ColorHex)
+		grpItems := itemsByGroup[grp.ID]
+		doneCount := 0
+		for _, it := range grpItems {
+			if it.Completed {
+				doneCount++
+			}
+		}
+		headerText := fmt.Sprintf("%s (%d/%d)", grp.Name, doneCount, len(grpItems))
+		parent := groupParent(*grp)
+		if parent != nil {
+			headerText = "↳ " + headerText
+		}
+		openCount := len(grpItems) - doneCount
+		if grp.WipLimit > 0 {
+			headerText += fmt.Sprintf("  WIP %d/%d", openCount, grp.WipLimit)
+		}
+		if grp.WipLimit > 0 && openCount > grp.WipLimit {
+			grpColor = overdueColor
+		}
+		headerLabel := canvas.NewText(headerText, color.White)
+		headerLabel.TextSize = scaledText(theme.TextSize())
+		headerLabel.TextStyle = fyne.TextStyle{Bold: true}
+		sortBtn := widget.NewButtonWithIcon("", theme.MenuIcon(), func() {
+			menu := fyne.NewMenu("Sort",
+				fyne.NewMenuItem("Sort by Date", func() { grp.SortMode = "date"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItem("Sort A-Z", func() { grp.SortMode = "alpha"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItem("Sort by Priority", func() { grp.SortMode = "priority"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItem("Show Overdue First", func() { grp.SortMode = "overdue"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItem("Recently Completed", func() { grp.SortMode = "recent"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItem("Recently Added", func() { grp.SortMode = "added"; saveGroups(); refreshKanban() }),
+				fyne.NewMenuItemSeparator(),
+				fyne.NewMenuItem("Move Column Left", func() { moveGroupColumn(grp.ID, -1) }),
+				fyne.NewMenuItem("Move Column Right", func() { moveGroupColumn(grp.ID, 1) }),
+				fyne.NewMenuItemSeparator(),
+				fyne.NewMenuItem("Mark All Complete", func() { bulkSetCompleted(grp.Name, groupItems(grp.ID), true) }),
+				fyne.NewMenuItem("Mark All Incomplete", func() { bulkSetCompleted(grp.Name, groupItems(grp.ID), false) }),
+				fyne.NewMenuItem("Clear Completed", func() { clearCompleted("'"+grp.Name+"'", groupItems(grp.ID)) }),
+				fyne.NewMenuItem("Delete All in Group", func() { bulkDelete(grp.Name, groupItems(grp.ID)) }),
+			)
+			widget.ShowPopUpMenuAtPosition(menu, mainWindow.Canvas(), fyne.CurrentApp().Driver().AbsolutePositionForObject(headerLabel))
+		})
+		headerBg := canvas.NewRectangle(grpColor)
+		headerBg.SetMinSize(fyne.NewSize(250, 40))
+		groupID := grp.ID
+		collapseBtn := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { setGroupCollapsed(groupID, true); refreshKanban() })
+		headerContent := container.NewBorder(nil, nil, collapseBtn, sortBtn, container.NewCenter(headerLabel))
+		if isGroupCollapsed(grp.ID) {
+			stripBg := canvas.NewRectangle(grpColor)
+			stripBg.SetMinSize(fyne.NewSize(48, 40))
+			abbrev := []rune(grp.Name)
+			if len(abbrev) > 3 {
+				abbrev = abbrev[:3]
+			}
+			nameText := canvas.NewText(string(abbrev), color.White)
+			nameText.TextSize = scaledText(theme.TextSize())
+			nameText.TextStyle = fyne.TextStyle{Bold: true}
+			countText := canvas.NewText(fmt.Sprintf("%d/%d", doneCount, len(grpItems)), color.White)
+			countText.TextSize = scaledText(10)
+			expandBtn := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { setGroupCollapsed(groupID, false); refreshKanban() })
+			strip := container.NewStack(stripBg, container.NewVBox(expandBtn, container.NewCenter(nameText), container.NewCenter(countText)))
+			highlight := canvas.NewRectangle(color.Transparent)
+			kanbanDropTargets = append(kanbanDropTargets, kanbanDropTarget{groupID: grp.ID, area: strip, highlight: highlight})
+			kanbanContainer.Add(container.NewStack(strip, highlight))
+			kanbanContainer.Add(layout.NewSpacer())
+			continue
+		}
+		itemsBox := container.NewVBox()
+		sort.Slice(grpItems, func(a, b int) bool {
+			pinA, pinB := grpItems[a].Pinned && !grpItems[a].Completed, grpItems[b].Pinned && !grpItems[b].Completed
+			if pinA != pinB {
+				return pinA
+			}
+			if grp.SortMode == "recent" {
+				if grpItems[a].Completed != grpItems[b].Completed {
+					return grpItems[a].Completed
+				}
+				if grpItems[a].CompletedAt != grpItems[b].CompletedAt {
+					return grpItems[a].CompletedAt > grpItems[b].CompletedAt
+				}
+			}
+			if grpItems[a].Completed != grpItems[b].Completed {
+				return !grpItems[a].Completed
+			}
+			if grp.SortMode == "added" && grpItems[a].CreatedAt != grpItems[b].CreatedAt {
+				return grpItems[a].CreatedAt > grpItems[b].CreatedAt
+			}
+			if grp.SortMode == "overdue" && isOverdue(grpItems[a]) != isOverdue(grpItems[b]) {
+				return isOverdue(grpItems[a])
+			}
+			if grp.SortMode == "alpha" {
+				return strings.ToLower(grpItems[a].Title) < strings.ToLower(grpItems[b].Title)
+			}
+			if grp.SortMode == "priority" && priorityRank(grpItems[a].Priority) != priorityRank(grpItems[b].Priority) {
+				return priorityRank(grpItems[a].Priority) > priorityRank(grpItems[b].Priority)
+			}
+			return grpItems[a].Start < grpItems[b].Start
+		})
+		for _, item := range grpItems {
+			cardBgColor := color.Color(color.RGBA{240, 240, 240, 255})
+			textColor := color.Color(color.Black)
+			overdue := isOverdue(item)
+			if item.Completed {
+				cardBgColor = color.RGBA{220, 220, 220, 255}
+				textColor = color.RGBA{150, 150, 150, 255}
+			} else if overdue {
+				cardBgColor = color.RGBA{253, 226, 224, 255}
+			}
+			cardBg := canvas.NewRectangle(cardBgColor)
+			cardBg.StrokeColor = color.RGBA{200, 200, 200, 255}
+			cardBg.StrokeWidth = 1
+			cardBg.CornerRadius = 5
+			var titleObj fyne.CanvasObject
+			if item.Completed {
+				titleObj = createStrikethroughText(seriesMark(item)+item.Title, textColor, scaledText(12))
+			} else {
+				t := canvas.NewText(seriesMark(item)+item.Title, textColor)
+				t.TextSize = scaledText(12)
+				titleObj = t
+			}
+			s, _ := parseItemTime(item.Start)
+			e, _ := parseItemTime(item.End)
+			dateStr := humanizeDate(s)
+			timeInfo := formatClock(s)
+			if item.Type == TypeEvent {
+				timeInfo = fmt.Sprintf("%s - %s", formatClock(s), formatClock(e))
+			}
+			dateText := fmt.Sprintf("%s | %s", dateStr, timeInfo)
+			if item.AllDay {
+				dateText = dateStr
+				if item.Type == TypeEvent && e.Format("2006-01-02") != s.Format("2006-01-02") {
+					dateText = fmt.Sprintf("%s - %s", dateStr, formatDate(e, "Mon, Jan 02"))
+				}
+			}
+			if item.Completed && item.CompletedAt != "" {
+				if t, err := parseItemTime(item.CompletedAt); err == nil {
+					dateText = fmt.Sprintf("%s | done %s", dateText, formatAgo(t))
+				}
+			}
+			if len(item.Subtasks) > 0 {
+				done := 0
+				for _, st := range item.Subtasks {
+					if st.Done {
+						done++
+					}
+				}
+				dateText = fmt.Sprintf("%s | %d/%d", dateText, done, len(item.Subtasks))
+			}
+			dateLabel := canvas.NewText(dateText, color.RGBA{100, 100, 100, 255})
+			dateLabel.TextSize = scaledText(10)
+			var dateRow fyne.CanvasObject = dateLabel
+			if overdue {
+				badge := canvas.NewText("OVERDUE", overdueColor)
+				badge.TextSize = scaledText(10)
+				badge.TextStyle = fyne.TextStyle{Bold: true}
+				dateRow = container.NewHBox(badge, dateLabel)
+			}
+			check := widget.NewCheck("", func(b bool) { setCompleted(item, b); saveData(); refreshCalendar(); refreshKanban() })
+			check.Checked = item.Completed
+			if activeCalendarName == allCalendarsName {
+				check.Disable()
+			}
+			var clipBtn fyne.CanvasObject
+			if len(item.Attachments) > 0 {
+				var btn *widget.Button
+				btn = widget.NewButtonWithIcon("", theme.MailAttachmentIcon(), func() { showAttachmentMenu(item, btn) })
+				btn.Importance = widget.LowImportance
+				clipBtn = btn
+			}
+			if item.Pinned {
+				pin := canvas.NewImageFromResource(pinIcon)
+				pin.FillMode = canvas.ImageFillContain
+				pin.SetMinSize(fyne.NewSize(14, 14))
+				if clipBtn != nil {
+					clipBtn = container.NewHBox(container.NewCenter(pin), clipBtn)
+				} else {
+					clipBtn = container.NewCenter(pin)
+				}
+			}
+			var cardBody *fyne.Container
+			if appSettings.CompactCards {
+				cardBody = container.NewStack(cardBg, container.New(layout.NewCustomPaddedLayout(0, 0, 4, 4), container.NewBorder(nil, nil, check, clipBtn, titleObj)))
+			} else {
+				cardLines := container.NewVBox(titleObj, dateRow)
+				if len(item.Tags) > 0 {
+					chips := container.NewHBox()
+					for _, tag := range item.Tags {
+						chipBg := canvas.NewRectangle(color.RGBA{210, 220, 235, 255})
+						chipBg.CornerRadius = 4
+						chipText := canvas.NewText(tag, color.RGBA{60, 70, 90, 255})
+						chipText.TextSize = scaledText(9)
+						chips.Add(container.NewStack(chipBg, container.New(layout.NewCustomPaddedLayout(1, 1, 4, 4), chipText)))
+					}
+					cardLines.Add(chips)
+				}
+				if t, err := parseItemTime(item.CreatedAt); err == nil {
+					addedText := canvas.NewText("added "+formatAgo(t), color.RGBA{150, 150, 150, 255})
+					addedText.TextSize = scaledText(9)
+					cardLines.Add(addedText)
+				}
+				content := container.NewBorder(nil, nil, check, clipBtn, cardLines)
+				cardBody = container.NewStack(cardBg, container.NewPadded(content))
+			}
+			if item.Priority == PriorityHigh && !item.Completed {
+				flag := canvas.NewRectangle(color.RGBA{231, 76, 60, 255})
+				flag.SetMinSize(fyne.NewSize(4, 0))
+				cardBody = container.NewBorder(nil, nil, flag, nil, cardBody)
+			}
+			if item.ColorHex != "" {
+				stripe := canvas.NewRectangle(parseHexColor(item.ColorHex))
+				stripe.SetMinSize(fyne.NewSize(6, 0))
+				cardBody = container.NewBorder(nil, nil, stripe, nil, cardBody)
+			}
+			clickCard := newKanbanCard(cardBody, item, groupID, len(kanbanDropTargets), len(itemsBox.Objects))
+			kanbanCards = append(kanbanCards, clickCard)
+			if appSettings.CompactCards {
+				clickCard.tooltip = itemTooltip(item)
+			}
+			var dropTarget *kanbanDropTarget
+			clickCard.onDrag = func(e *fyne.DragEvent) {
+				var hovered *kanbanDropTarget
+				for i := range kanbanDropTargets {
+					if objectContains(kanbanDropTargets[i].area, e.AbsolutePosition) {
+						hovered = &kanbanDropTargets[i]
+						break
+					}
+				}
+				if hovered == dropTarget {
+					return
+				}
+				if dropTarget != nil {
+					dropTarget.highlight.StrokeWidth = 0
+					dropTarget.highlight.Refresh()
+				}
+				dropTarget = hovered
+				if dropTarget != nil && !slices.Contains(itemGroupIDs(item), dropTarget.groupID) {
+					dropTarget.highlight.StrokeColor = theme.PrimaryColor()
+					dropTarget.highlight.StrokeWidth = 3
+					dropTarget.highlight.Refresh()
+				}
+			}
+			clickCard.onDragEnd = func() {
+				if dropTarget == nil {
+					return
+				}
+				target := dropTarget
+				dropTarget = nil
+				target.highlight.StrokeWidth = 0
+				target.highlight.Refresh()
+				if slices.Contains(itemGroupIDs(item), target.groupID) || blockReadOnly() {
+					return
+				}
+				replaceItemGroup(item, groupID, target.groupID)
+				saveData()
+				refreshCalendar()
+				refreshKanban()
+			}
+			clickCard.onRight = func(e *fyne.PointEvent) { showItemActions(item, e.AbsolutePosition) }
+			itemsBox.Add(clickCard)
+		}
+		var progress float32
+		if len(grpItems) > 0 {
+			progress = float32(doneCount) / float32(len(grpItems))
+		}
+		progressBar := container.New(&progressLayout{fraction: progress}, canvas.NewRectangle(color.RGBA{0, 0, 0, 60}), canvas.NewRectangle(grpColor))
+		header := container.NewVBox(container.NewStack(headerBg, headerContent), progressBar)
+		if parent != nil {
+			parentBar := canvas.NewRectangle(parseHexColor(parent.ColorHex))
+			parentBar.SetMinSize(fyne.NewSize(0, 4))
+			header = container.NewVBox(parentBar, container.NewStack(headerBg, headerContent), progressBar)
+		}
+		column := container.NewBorder(header, nil, nil, nil, container.NewVScroll(container.NewPadded(itemsBox)))
+		highlight := canvas.NewRectangle(color.Transparent)
+		kanbanDropTargets = append(kanbanDropTargets, kanbanDropTarget{groupID: grp.ID, area: column, highlight: highlight})
+		kanbanContainer.Add(container.NewStack(column, highlight))
+		kanbanContainer.Add(layout.NewSpacer())
+	}
+	kanbanContainer.Refresh()
+	if kanbanRefocusID != "" {
+		for _, k := range kanbanCards {
+			if k.item.ID == kanbanRefocusID {
+				mainWindow.Canvas().Focus(k)
+				break
+			}
+		}
+		kanbanRefocusID = ""
+	}
+	refreshStats()
+	refreshAgenda()
+}
+func showItemActions(item *TodoItem, pos fyne.Position) {
+	if blockReadOnly() {
+		return
+	}
+	statusLabel := "Mark Complete"
+	if item.Completed {
+		statusLabel = "Mark Incomplete"
+	}
+	archiveLabel := "Archive"
+	if item.Archived {
+		archiveLabel = "Unarchive"
+	}
+	convertLabel := "Convert to Event"
+	if item.Type == TypeEvent {
+		convertLabel = "Convert to Task"
+	}
+	pinLabel := "Pin to Top"
+	if item.Pinned {
+		pinLabel = "Unpin"
+	}
+	menu := fyne.NewMenu("Actions",
+		fyne.NewMenuItem(statusLabel, func() { setCompleted(item, !item.Completed); saveData(); refreshCalendar(); refreshKanban() }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Move to...", func() { showMoveDialog(item) }),
+		fyne.NewMenuItem("Reschedule...", func() { showRescheduleDialog(item) }),
+		fyne.NewMenuItem("Duplicate", func() { duplicateItem(item) }),
+		fyne.NewMenuItem(convertLabel, func() { convertItemType(item) }),
+		fyne.NewMenuItem(pinLabel, func() { item.Pinned = !item.Pinned; saveData(); refreshKanban() }),
+		fyne.NewMenuItem(archiveLabel, func() {
+			item.Archived = !item.Archived
+			if item.Archived && item.ID == currentEditItemID {
+				resetSidebar()
+			}
+			saveData()
+			refreshCalendar()
+			refreshKanban()
+		}),
+		fyne.NewMenuItem("Delete", func() { performSmartDelete(item.ID) }),
+	)
+	if item.SeriesID != "" {
+		menu.Items = slices.Insert(menu.Items, len(menu.Items)-1, fyne.NewMenuItem("Skip This Occurrence", func() { skipOccurrence(item) }))
+	}
+	if mainTabs != nil && mainTabs.SelectedIndex() != 0 {
+		menu.Items = slices.Insert(menu.Items, 2, fyne.NewMenuItem("Show in Calendar", func() { showInCalendar(item) }))
+	}
+	widget.ShowPopUpMenuAtPosition(menu, mainWindow.Canvas(), pos)
+}
+
+// showInCalendar switches to the Calendar tab with the item's start date in view and selected.
+func showInCalendar(item *TodoItem) {
+	s, err := parseItemTime(item.Start)
+	if err != nil {
+		return
+	}
+	day := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, time.Local)
+	confirmDiscardSidebar(func() {
+		currentViewDate = day
+		mainTabs.SelectIndex(0)
+		applyCalendarDay(day)
+	})
+}
+
+// groupItems is every non-archived item in a group, regardless of the search and tag filters limiting the column.
+func groupItems(groupID string) []*TodoItem {
+	targets := []*TodoItem{}
+	for i := range items {
+		if !items[i].Archived && slices.Contains(itemGroupIDs(&items[i]), groupID) {
+			targets = append(targets, &items[i])
+		}
+	}
+	return targets
+}
+func bulkSetCompleted(groupName string, targets []*TodoItem, done bool) {
+	if len(targets) == 0 || blockReadOnly() {
+		return
+	}
+	ids := make(map[string]bool)
+	for _, t := range targets {
+		ids[t.ID] = true
+	}
+	verb := "complete"
+	if !done {
+		verb = "incomplete"
+	}
+	dialog.ShowConfirm("Update Group", fmt.Sprintf("Mark %d item(s) in '%s' as %s?", len(ids), groupName, verb), func(ok bool) {
+		if !ok {
+			return
+		}
+		for i := range items {
+			if ids[items[i].ID] {
+				setCompleted(&items[i], done)
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+func bulkDelete(groupName string, targets []*TodoItem) {
+	if len(targets) == 0 || blockReadOnly() {
+		return
+	}
+	ids := make(map[string]bool)
+	for _, t := range targets {
+		ids[t.ID] = true
+	}
+	dialog.ShowConfirm("Delete Group Items", fmt.Sprintf("Delete %d item(s) in '%s'? This can't be undone.", len(ids), groupName), func(ok bool) {
+		if !ok {
+			return
+		}
+		if ids[currentEditItemID] {
+			resetSidebar()
+		}
+		items = slices.DeleteFunc(items, func(it TodoItem) bool { return ids[it.ID] })
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+
+// clearCompleted deletes the completed items among targets. Occurrences are removed by ID, so the
+// open occurrences of a recurring series stay where they are.
+func clearCompleted(scope string, targets []*TodoItem) {
+	if blockReadOnly() {
+		return
+	}
+	ids := make(map[string]bool)
+	for _, t := range targets {
+		if t.Completed {
+			ids[t.ID] = true
+		}
+	}
+	if len(ids) == 0 {
+		dialog.ShowInformation("Clear Completed", "There are no completed items in "+scope+".", mainWindow)
+		return
+	}
+	dialog.ShowConfirm("Clear Completed", fmt.Sprintf("Delete %d completed item(s) in %s? This can't be undone.", len(ids), scope), func(ok bool) {
+		if !ok {
+			return
+		}
+		if ids[currentEditItemID] {
+			resetSidebar()
+		}
+		items = slices.DeleteFunc(items, func(it TodoItem) bool { return ids[it.ID] })
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+
+// itemGroupIDs lists every group an item belongs to, the primary GroupID first. GroupIDs is only stored
+// for items in more than one group; the primary group still decides the item's calendar color.
+func itemGroupIDs(item *TodoItem) []string {
+	ids := []string{item.GroupID}
+	for _, id := range item.GroupIDs {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+func setItemGroups(item *TodoItem, ids []string) {
+	item.GroupIDs = nil
+	item.GroupID = ""
+	if len(ids) == 0 {
+		return
+	}
+	item.GroupID = ids[0]
+	if all := itemGroupIDs(&TodoItem{GroupID: ids[0], GroupIDs: ids}); len(all) > 1 {
+		item.GroupIDs = all
+	}
+}
+
+// replaceItemGroup swaps one group membership for another, keeping the item's other groups.
+func replaceItemGroup(item *TodoItem, from, to string) {
+	ids := itemGroupIDs(item)
+	if i := slices.Index(ids, from); i >= 0 {
+		ids[i] = to
+		setItemGroups(item, ids)
+	}
+}
+
+// resolveImportGroups turns an imported item's group names into memberships, creating groups that don't exist yet.
+func resolveImportGroups(p *TodoItem) {
+	if p.GroupName == "" {
+		return
+	}
+	ids := []string{groupIDForName(p.GroupName)}
+	for _, name := range p.ImportGroups {
+		ids = append(ids, groupIDForName(name))
+	}
+	setItemGroups(p, ids)
+	p.GroupName, p.ImportGroups = "", nil
+}
+func refreshAlsoGroupsButton() {
+	if sbAlsoGroupsBtn == nil {
+		return
+	}
+	names := []string{}
+	for _, id := range sbAlsoGroups {
+		if i := slices.IndexFunc(groups, func(g Group) bool { return g.ID == id }); i >= 0 {
+			names = append(names, groups[i].Name)
+		}
+	}
+	if len(names) == 0 {
+		sbAlsoGroupsBtn.SetText("Also in other groups…")
+		return
+	}
+	sbAlsoGroupsBtn.SetText("Also in: " + strings.Join(names, ", "))
+}
+func showAlsoGroupsDialog() {
+	names := []string{}
+	selected := []string{}
+	for _, i := range columnOrder() {
+		g := groups[i]
+		if g.Name == sbGroupSelect.Selected {
+			continue
+		}
+		names = append(names, g.Name)
+		if slices.Contains(sbAlsoGroups, g.ID) {
+			selected = append(selected, g.Name)
+		}
+	}
+	if len(names) == 0 {
+		dialog.ShowInformation("Other Groups", "There are no other groups to add this item to.", mainWindow)
+		return
+	}
+	checks := widget.NewCheckGroup(names, nil)
+	checks.Selected = selected
+	dialog.ShowCustomConfirm("Also Show In", "Apply", "Cancel", checks, func(ok bool) {
+		if !ok {
+			return
+		}
+		sbAlsoGroups = nil
+		for _, name := range checks.Selected {
+			sbAlsoGroups = append(sbAlsoGroups, groupIDForName(name))
+		}
+		refreshAlsoGroupsButton()
+		autoSave()
+	}, mainWindow)
+}
+func duplicateItem(item *TodoItem) {
+	clone := *item
+	clone.ID = fmt.Sprintf("%d", time.Now().UnixNano())
+	clone.SeriesID = ""
+	clone.Exceptions = nil
+	if item.RecurrenceRule != nil {
+		rule := *item.RecurrenceRule
+		clone.RecurrenceRule = &rule
+	}
+	clone.Completed = false
+	clone.CompletedAt = ""
+	clone.CreatedAt = time.Now().Format("2006-01-02 15:04")
+	clone.Archived = false
+	clone.Subtasks = slices.Clone(item.Subtasks)
+	clone.Tags = slices.Clone(item.Tags)
+	clone.GroupIDs = slices.Clone(item.GroupIDs)
+	items = append(items, clone)
+	saveData()
+	refreshCalendar()
+	refreshKanban()
+	startEditing(&items[len(items)-1])
+}
+func isVisible(item *TodoItem) bool {
+	if invalidDateIDs[item.ID] {
+		return false
+	}
+	if item.Archived && !appSettings.ShowArchived {
+		return false
+	}
+	if !slices.ContainsFunc(itemGroupIDs(item), func(id string) bool { return !isGroupHidden(id) }) {
+		return false
+	}
+	if tagFilter != "" && !slices.Contains(item.Tags, tagFilter) {
+		return false
+	}
+	return matchesSearch(item)
+}
+func matchesSearch(item *TodoItem) bool {
+	if searchQuery == "" {
+		return true
+	}
+	q := strings.ToLower(searchQuery)
+	if strings.Contains(strings.ToLower(item.Title), q) {
+		return true
+	}
+	for _, tag := range item.Tags {
+		if strings.Contains(strings.ToLower(tag), q) {
+			return true
+		}
+	}
+	return false
+}
+func parseTags(s string) []string {
+	tags := []string{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+func refreshTagFilter() {
+	tags := []string{}
+	for _, item := range items {
+		for _, t := range item.Tags {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	if tagFilter != "" && !slices.Contains(tags, tagFilter) {
+		tags = append(tags, tagFilter)
+	}
+	tagFilterSelect.SetOptions(append([]string{"All Tags"}, tags...))
+}
+
+// syncSidebarDates pushes the edited item's dates back into the pickers after it was moved elsewhere, so autoSave doesn't undo the move.
+func syncSidebarDates() {
+	for i := range items {
+		if items[i].ID != currentEditItemID {
+			continue
+		}
+		s, _ := parseItemTime(items[i].Start)
+		e, _ := parseItemTime(items[i].End)
+		setTaskDate(s.Format("2006-01-02"))
+		setStartDate(s.Format("2006-01-02"))
+		setEndDate(e.Format("2006-01-02"))
+		return
+	}
+}
+func showRescheduleDialog(item *TodoItem) {
+	s, _ := parseItemTime(item.Start)
+	showDatePicker(mainWindow, s, func(day time.Time) {
+		days := daysBetween(s, day)
+		if days == 0 {
+			return
+		}
+		chooseSeriesScope(item, "Reschedule Recurring", "Repeating item. Reschedule?", func(targets []*TodoItem) {
+			for _, t := range targets {
+				shiftItemDays(t, days)
+			}
+			syncSidebarDates()
+			selectedCalendarDate = day
+			currentViewDate = day
+			saveData()
+			refreshCalendar()
+			refreshKanban()
+		})
+	})
+}
+
+// convertItemType flips a task into an event (asking for an end, +1h by default) or an event into a task (End collapses onto Start).
+func convertItemType(item *TodoItem) {
+	apply := func(toEvent bool, duration time.Duration) {
+		chooseSeriesScope(item, "Convert Recurring", "Repeating item. Convert?", func(targets []*TodoItem) {
+			for _, t := range targets {
+				s, _ := parseItemTime(t.Start)
+				if toEvent {
+					t.Type = TypeEvent
+					t.End = s.Add(duration).Format("2006-01-02 15:04")
+				} else {
+					t.Type = TypeTask
+					t.End = t.Start
+				}
+			}
+			if currentEditItemID == item.ID {
+				loadSidebarItem(item)
+			}
+			saveData()
+			refreshCalendar()
+			refreshKanban()
+		})
+	}
+	if item.Type == TypeEvent {
+		apply(false, 0)
+		return
+	}
+	s, _ := parseItemTime(item.Start)
+	if item.AllDay {
+		apply(true, time.Date(s.Year(), s.Month(), s.Day(), 23, 59, 0, 0, time.Local).Sub(s))
+		return
+	}
+	end := s.Add(time.Hour)
+	btnDate, getDate, setDate := createDatePickerButton(mainWindow, nil)
+	setDate(end.Format("2006-01-02"))
+	h, m, ap, timeBox, setTime := createTimePicker(nil)
+	setTime(formatTimeParts(end))
+	form := []*widget.FormItem{widget.NewFormItem("Ends", container.NewVBox(btnDate, timeBox))}
+	dialog.ShowForm("Convert to Event", "Convert", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		e, err := parseItemTime(combineDateTime(getDate(), h.Selected, m.Selected, ap.Selected))
+		if err != nil || !e.After(s) {
+			dialog.ShowError(fmt.Errorf("the end must be after the start (%s)", formatClock(s)), mainWindow)
+			return
+		}
+		apply(true, e.Sub(s))
+	}, mainWindow)
+}
+func showMoveDialog(item *TodoItem) {
+	var d dialog.Dialog
+	opts := []string{}
+	for _, g := range groups {
+		if !slices.Contains(itemGroupIDs(item), g.ID) {
+			opts = append(opts, g.Name)
+		}
+	}
+	sel := widget.NewSelect(opts, nil)
+	sel.PlaceHolder = "Select Group"
+	btnConfirm := widget.NewButton("Move", func() {
+		if sel.Selected == "" {
+			return
+		}
+		for _, g := range groups {
+			if g.Name == sel.Selected {
+				replaceItemGroup(item, item.GroupID, g.ID)
+				break
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+		d.Hide()
+	})
+	d = dialog.NewCustom("Move Item", "Cancel", container.NewPadded(container.NewVBox(widget.NewLabel(fmt.Sprintf("Move '%s' to:", item.Title)), sel, btnConfirm)), mainWindow)
+	d.Show()
+}
+
+// --- IMPORT / EXPORT / HELPERS ---
+
+func showSettingsDialog() {
+	var d dialog.Dialog
+
+	themeSelect := widget.NewSelect([]string{"Light", "Dark"}, func(s string) {
+		currentTheme = s
+		applyTheme()
+	})
+	themeSelect.SetSelected(currentTheme)
+
+	accentPreview := canvas.NewRectangle(theme.PrimaryColor())
+	accentPreview.SetMinSize(fyne.NewSize(50, 20))
+	setAccent := func(hex string) {
+		appSettings.AccentColor = hex
+		saveSettings()
+		applyTheme()
+		accentPreview.FillColor = theme.PrimaryColor()
+		accentPreview.Refresh()
+		refreshCalendar()
+		refreshKanban()
+	}
+	accentGrid := container.NewGridWithColumns(6)
+	for _, c := range PresetColors {
+		hex := c
+		swatch := canvas.NewRectangle(parseHexColor(hex))
+		swatch.SetMinSize(fyne.NewSize(24, 24))
+		accentGrid.Add(newClickableBox(container.NewStack(swatch), func() { setAccent(hex) }))
+	}
+	accentEntry := widget.NewEntry()
+	accentEntry.PlaceHolder = "#RRGGBB"
+	accentEntry.SetText(appSettings.AccentColor)
+	accentEntry.OnSubmitted = func(s string) {
+		if hex, ok := normalizeHexColor(s); ok {
+			setAccent(hex)
+		}
+	}
+	accentReset := widget.NewButton("Default", func() { accentEntry.SetText(""); setAccent("") })
+
+	calNames := append(calendarNames(), allCalendarsName)
+	calLabels := []string{}
+	for _, name := range calNames {
+		calLabels = append(calLabels, calendarLabel(name))
+	}
+	calSelect := widget.NewSelect(calLabels, func(s string) {
+		if i := slices.Index(calLabels, s); i >= 0 && calNames[i] != activeCalendarName {
+			switchCalendar(calNames[i])
+			d.Hide()
+		}
+	})
+	calSelect.SetSelected(calendarLabel(activeCalendarName))
+	calSwatch := canvas.NewRectangle(calendarColor(activeCalendarName))
+	calSwatch.SetMinSize(fyne.NewSize(14, 14))
+	switcherBtn := widget.NewButtonWithIcon("", theme.SearchIcon(), func() { d.Hide(); showCalendarSwitcher() })
+
+	clockSelect := widget.NewSelect([]string{"12-hour", "24-hour"}, func(s string) {
+		use24 := s == "24-hour"
+		if use24 == appSettings.Use24Hour {
+			return
+		}
+		appSettings.Use24Hour = use24
+		saveSettings()
+		for _, apply := range timePickerFormatters {
+			apply()
+		}
+		refreshCalendar()
+		refreshKanban()
+	})
+	if appSettings.Use24Hour {
+		clockSelect.SetSelected("24-hour")
+	} else {
+		clockSelect.SetSelected("12-hour")
+	}
+	languageSelect := widget.NewSelect(languageOptions(), func(s string) {
+		if s == "English" {
+			s = ""
+		}
+		if s == appSettings.Language {
+			return
+		}
+		appSettings.Language = s
+		saveSettings()
+		refreshWeekdayHeader()
+		refreshMiniCalendar()
+		refreshCalendar()
+		refreshKanban()
+	})
+	if appSettings.Language == "" {
+		languageSelect.SetSelected("English")
+	} else {
+		languageSelect.SetSelected(appSettings.Language)
+	}
+
+	stepSelect := widget.NewSelect([]string{"1", "5", "15", "30"}, func(s string) {
+		n, _ := strconv.Atoi(s)
+		if n > 0 && n != appSettings.MinuteStep {
+			appSettings.MinuteStep = n
+			saveSettings()
+			for _, apply := range timePickerFormatters {
+				apply()
+			}
+		}
+	})
+	stepSelect.SetSelected(strconv.Itoa(appSettings.MinuteStep))
+
+	cellLimits := []string{"1", "2", "3", "4", "5", "6", "8", "10"}
+	cellLimitSelect := widget.NewSelect(cellLimits, func(s string) {
+		n, _ := strconv.Atoi(s)
+		if n > 0 && n != appSettings.MaxItemsPerCell {
+			appSettings.MaxItemsPerCell = n
+			saveSettings()
+			refreshCalendar()
+		}
+	})
+	cellLimitSelect.SetSelected(strconv.Itoa(appSettings.MaxItemsPerCell))
+
+	scaleSelect := widget.NewSelect([]string{"90%", "100%", "125%", "150%", "175%", "200%"}, func(s string) {
+		n, _ := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if n > 0 && n != textScalePercent() {
+			appSettings.TextScale = n
+			saveSettings()
+			refreshCalendar()
+			refreshKanban()
+		}
+	})
+	scaleSelect.SetSelected(fmt.Sprintf("%d%%", textScalePercent()))
+
+	backupSelect := widget.NewSelect([]string{"Off", "5", "10", "20", "50"}, func(s string) {
+		n, _ := strconv.Atoi(s)
+		if n != appSettings.BackupCount {
+			appSettings.BackupCount = n
+			saveSettings()
+		}
+	})
+	if appSettings.BackupCount <= 0 {
+		backupSelect.SetSelected("Off")
+	} else {
+		backupSelect.SetSelected(strconv.Itoa(appSettings.BackupCount))
+	}
+
+	defaultTypeSelect := widget.NewSelect([]string{string(TypeTask), string(TypeEvent)}, func(s string) {
+		appSettings.DefaultType = s
+		saveSettings()
+	})
+	defaultTypeSelect.SetSelected(defaultItemType())
+	defaultGroupSelect := widget.NewSelect(nil, func(s string) {
+		if s == "First group" {
+			s = ""
+		}
+		appSettings.DefaultGroup = s
+		saveSettings()
+	})
+	defaultGroupSelect.Options = []string{"First group"}
+	for _, g := range groups {
+		defaultGroupSelect.Options = append(defaultGroupSelect.Options, g.Name)
+	}
+	if appSettings.DefaultGroup != "" && !slices.Contains(defaultGroupSelect.Options, appSettings.DefaultGroup) {
+		defaultGroupSelect.Options = append(defaultGroupSelect.Options, appSettings.DefaultGroup)
+	}
+	if appSettings.DefaultGroup == "" {
+		defaultGroupSelect.SetSelected("First group")
+	} else {
+		defaultGroupSelect.SetSelected(appSettings.DefaultGroup)
+	}
+	startLabels, startValues := []string{}, []string{}
+	for m := 0; m < 24*60; m += 30 {
+		t := time.Date(2000, 1, 1, m/60, m%60, 0, 0, time.Local)
+		startLabels = append(startLabels, formatClock(t))
+		startValues = append(startValues, t.Format("15:04"))
+	}
+	defaultStartSelect := widget.NewSelect(startLabels, func(s string) {
+		if i := slices.Index(startLabels, s); i >= 0 {
+			appSettings.DefaultStart = startValues[i]
+			saveSettings()
+		}
+	})
+	if i := slices.Index(startValues, appSettings.DefaultStart); i >= 0 {
+		defaultStartSelect.SetSelected(startLabels[i])
+	} else {
+		defaultStartSelect.SetSelected(startLabels[18])
+	}
+	workSelect := func(current *string, fallback int) *widget.Select {
+		sel := widget.NewSelect(startLabels, func(s string) {
+			if i := slices.Index(startLabels, s); i >= 0 && startValues[i] != *current {
+				*current = startValues[i]
+				saveSettings()
+				refreshCalendar()
+			}
+		})
+		if i := slices.Index(startValues, *current); i >= 0 {
+			sel.SetSelected(startLabels[i])
+		} else {
+			sel.SetSelected(startLabels[fallback])
+		}
+		return sel
+	}
+	workStartSelect := workSelect(&appSettings.WorkStart, 18)
+	workEndSelect := workSelect(&appSettings.WorkEnd, 34)
+	weekdayNames := []string{}
+	for wd := time.Monday; wd <= time.Saturday+1; wd++ {
+		weekdayNames = append(weekdayNames, formatDate(time.Date(2024, 1, int(wd), 0, 0, 0, 0, time.Local), "Mon"))
+	}
+	weekendChecks := widget.NewCheckGroup(weekdayNames, nil)
+	weekendChecks.Horizontal = true
+	for i := range weekdayNames {
+		if slices.Contains(appSettings.WeekendDays, (i+1)%7) {
+			weekendChecks.Selected = append(weekendChecks.Selected, weekdayNames[i])
+		}
+	}
+	weekendChecks.OnChanged = func(sel []string) {
+		appSettings.WeekendDays = []int{}
+		for i, name := range weekdayNames {
+			if slices.Contains(sel, name) {
+				appSettings.WeekendDays = append(appSettings.WeekendDays, (i+1)%7)
+			}
+		}
+		saveSettings()
+		refreshCalendar()
+	}
+	durationMinutes := []int{15, 30, 45, 60, 90, 120, 180, 240}
+	durationLabels := []string{}
+	for _, m := range durationMinutes {
+		durationLabels = append(durationLabels, formatDuration(time.Duration(m)*time.Minute))
+	}
+	defaultDurationSelect := widget.NewSelect(durationLabels, func(s string) {
+		if i := slices.Index(durationLabels, s); i >= 0 {
+			appSettings.DefaultDuration = durationMinutes[i]
+			saveSettings()
+		}
+	})
+	if i := slices.Index(durationMinutes, defaultDurationMinutes()); i >= 0 {
+		defaultDurationSelect.SetSelected(durationLabels[i])
+	}
+
+	showArchivedCheck := widget.NewCheck("Show archived items", func(b bool) {
+		if b != appSettings.ShowArchived {
+			appSettings.ShowArchived = b
+			saveSettings()
+			refreshCalendar()
+			refreshKanban()
+		}
+	})
+	showArchivedCheck.SetChecked(appSettings.ShowArchived)
+	inboxCheck := widget.NewCheck("Inbox column for items without a group", func(b bool) {
+		if b == appSettings.InboxEnabled {
+			return
+		}
+		appSettings.InboxEnabled = b
+		saveSettings()
+		if !b {
+			if sbGroupSelect.Selected == inboxGroupName {
+				sbGroupSelect.SetSelected("")
+			}
+			if activeCalendarName != allCalendarsName {
+				recoverOrphanedItems()
+			}
+		}
+		updateGroupDropdown()
+		refreshCalendar()
+		refreshKanban()
+	})
+	inboxCheck.SetChecked(appSettings.InboxEnabled)
+	watchCheck := widget.NewCheck("Reload when data files change on disk", func(b bool) {
+		if b != appSettings.WatchFiles {
+			appSettings.WatchFiles = b
+			saveSettings()
+			startFileWatch()
+		}
+	})
+	watchCheck.SetChecked(appSettings.WatchFiles)
+	autoCompleteCheck := widget.NewCheck("Complete tasks when all checklist items are done", func(b bool) {
+		appSettings.AutoComplete = b
+		saveSettings()
+	})
+	autoCompleteCheck.SetChecked(appSettings.AutoComplete)
+	groupNames := []string{}
+	for _, g := range groups {
+		groupNames = append(groupNames, g.Name)
+	}
+	archiveGroupSelect := widget.NewSelect(groupNames, nil)
+	archiveGroupSelect.PlaceHolder = "Select Group"
+	archiveBtn := widget.NewButton("Archive All Completed", func() {
+		if blockReadOnly() {
+			return
+		}
+		groupID := ""
+		for _, g := range groups {
+			if g.Name == archiveGroupSelect.Selected {
+				groupID = g.ID
+				break
+			}
+		}
+		if groupID == "" {
+			return
+		}
+		archived := 0
+		for i := range items {
+			if slices.Contains(itemGroupIDs(&items[i]), groupID) && items[i].Completed && !items[i].Archived {
+				items[i].Archived = true
+				archived++
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+		dialog.ShowInformation("Archived", fmt.Sprintf("%d items archived", archived), mainWindow)
+	})
+
+	manageCalBtn := widget.NewButton("Create / Delete Calendars", func() { d.Hide(); showCalendarManager() })
+	btnImport := widget.NewButtonWithIcon("Import .ICS", theme.FolderOpenIcon(), func() { importICS(); d.Hide() })
+	btnExport := widget.NewButtonWithIcon("Export .ICS", theme.DocumentSaveIcon(), func() { exportICS() })
+	btnExportCSV := widget.NewButtonWithIcon("Export CSV", theme.DocumentSaveIcon(), func() { exportCSV() })
+	btnImportCSV := widget.NewButtonWithIcon("Import CSV", theme.FolderOpenIcon(), func() { importCSV(); d.Hide() })
+	btnExportBackup := widget.NewButtonWithIcon("Export Backup", theme.DocumentSaveIcon(), func() { exportBackup() })
+	btnImportBackup := widget.NewButtonWithIcon("Import Backup", theme.FolderOpenIcon(), func() { importBackup(); d.Hide() })
+	btnPrintMonth := widget.NewButtonWithIcon("Export Month as PDF / PNG", theme.DocumentPrintIcon(), func() { d.Hide(); exportMonthPrint() })
+	btnCalDAV := widget.NewButtonWithIcon("CalDAV Sync", theme.ViewRefreshIcon(), func() { d.Hide(); syncCalDAV() })
+	btnCalDAVSetup := widget.NewButtonWithIcon("CalDAV Server...", theme.SettingsIcon(), func() { d.Hide(); showCalDAVSettings() })
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("App Settings", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		widget.NewLabel("Theme"), themeSelect,
+		container.NewBorder(nil, nil, widget.NewLabel("Accent Color"), accentPreview), accentGrid,
+		container.NewBorder(nil, nil, nil, accentReset, accentEntry),
+		widget.NewLabel("Time Format"), clockSelect,
+		widget.NewLabel("Date Language"), languageSelect,
+		widget.NewLabel("Working Hours"), container.NewGridWithColumns(2, workStartSelect, workEndSelect),
+		widget.NewLabel("Weekend Days"), container.NewHScroll(weekendChecks),
+		widget.NewLabel("Minute Step"), stepSelect,
+		widget.NewLabel("Items Shown Per Day"), cellLimitSelect,
+		widget.NewLabel("Text Size"), scaleSelect,
+		autoCompleteCheck,
+		widget.NewSeparator(),
+		widget.NewLabel("New Items"),
+		container.NewGridWithColumns(2, widget.NewLabel("Type"), defaultTypeSelect),
+		container.NewGridWithColumns(2, widget.NewLabel("Group"), defaultGroupSelect),
+		container.NewGridWithColumns(2, widget.NewLabel("Start Time"), defaultStartSelect),
+		container.NewGridWithColumns(2, widget.NewLabel("Event Length"), defaultDurationSelect),
+		widget.NewLabel("Archive"), showArchivedCheck, inboxCheck, container.NewGridWithColumns(2, archiveGroupSelect, archiveBtn),
+		widget.NewSeparator(),
+		widget.NewLabel("Active Calendar"), container.NewBorder(nil, nil, container.NewCenter(calSwatch), switcherBtn, calSelect), manageCalBtn,
+		widget.NewLabel("Backups Kept Per Calendar"), backupSelect, watchCheck,
+		widget.NewSeparator(),
+		widget.NewLabel("Data Transfer"), container.NewGridWithColumns(2, btnImport, btnExport), container.NewGridWithColumns(2, btnImportCSV, btnExportCSV),
+		container.NewGridWithColumns(2, btnImportBackup, btnExportBackup),
+		btnPrintMonth,
+		container.NewGridWithColumns(2, btnCalDAVSetup, btnCalDAV),
+	)
+	d = dialog.NewCustom("Settings", "Close", container.NewVScroll(container.NewPadded(content)), mainWindow)
+	d.Resize(fyne.NewSize(420, 600))
+	d.Show()
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if old, err := os.ReadFile(path); err == nil && json.Valid(old) {
+		_ = os.WriteFile(path+".bak", old, 0644)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	ownWrites[path] = data
+	return nil
+}
+func readJSONFile(path string, v any) error {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(file, v); err == nil {
+		return nil
+	}
+	if bak, bakErr := os.ReadFile(path + ".bak"); bakErr == nil && json.Unmarshal(bak, v) == nil {
+		return nil
+	}
+	_ = os.WriteFile(path+".corrupt", file, 0644)
+	return fmt.Errorf("%s is corrupt (kept a copy as %s.corrupt): %w", path, path, err)
+}
+func initDataDir() {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(configDir, "SimpleKanbanCalendar")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	dataDir = dir
+	if _, err := os.Stat(dataPath("calendars_meta.json")); !os.IsNotExist(err) {
+		return
+	}
+	// Carry over files written next to the binary by older versions.
+	legacy, _ := filepath.Glob("*.json")
+	for _, name := range legacy {
+		if name != "calendars_meta.json" && name != "app_settings.json" && !strings.HasSuffix(name, "_data.json") && !strings.HasSuffix(name, "_groups.json") {
+			continue
+		}
+		if content, err := os.ReadFile(name); err == nil {
+			_ = os.WriteFile(dataPath(name), content, 0644)
+		}
+	}
+}
+func dataPath(name string) string {
+	return filepath.Join(dataDir, name)
+}
+func loadSettings() {
+	_ = readJSONFile(dataPath("app_settings.json"), &appSettings)
+}
+func saveSettings() {
+	file, _ := json.MarshalIndent(appSettings, "", " ")
+	_ = writeFileAtomic(dataPath("app_settings.json"), file)
+}
+func getFilenames() (string, string) {
+	return calendarFilenames(activeCalendarName)
+}
+func calendarFilenames(name string) (string, string) {
+	prefix := strings.ReplaceAll(name, " ", "_")
+	return dataPath(prefix + "_data.json"), dataPath(prefix + "_groups.json")
+}
+func loadCalendarList() {
+	if err := readJSONFile(dataPath("calendars_meta.json"), &availableCalendars); err != nil && !os.IsNotExist(err) {
+		dialog.ShowError(err, mainWindow)
+	}
+	if len(availableCalendars) == 0 {
+		availableCalendars = []CalendarMeta{{Name: "Default"}}
+		saveCalendarList()
+	}
+}
+func calendarNames() []string {
+	names := []string{}
+	for _, c := range availableCalendars {
+		names = append(names, c.Name)
+	}
+	return names
+}
+func calendarIndex(name string) int {
+	return slices.IndexFunc(availableCalendars, func(c CalendarMeta) bool { return c.Name == name })
+}
+
+// calendarLabel is the name with the calendar's icon in front, for selects, lists and the window title.
+func calendarLabel(name string) string {
+	if i := calendarIndex(name); i >= 0 && availableCalendars[i].Icon != "" {
+		return availableCalendars[i].Icon + " " + name
+	}
+	return name
+}
+func calendarColor(name string) color.Color {
+	if i := calendarIndex(name); i >= 0 && availableCalendars[i].ColorHex != "" {
+		return parseHexColor(availableCalendars[i].ColorHex)
+	}
+	return color.Transparent
+}
+func updateWindowTitle() {
+	if mainWindow != nil {
+		mainWindow.SetTitle(calendarLabel(activeCalendarName) + " — Go Local Calendar & Kanban")
+	}
+}
+func saveCalendarList() {
+	file, _ := json.MarshalIndent(availableCalendars, "", " ")
+	_ = writeFileAtomic(dataPath("calendars_meta.json"), file)
+}
+func switchCalendar(name string) {
+	flushPendingSave()
+	activeCalendarName = name
+	items = []TodoItem{}
+	groups = []Group{}
+	if name == allCalendarsName {
+		loadAllCalendars()
+	} else {
+		loadGroups()
+		loadData()
+	}
+	startFileWatch()
+	updateWindowTitle()
+	refreshCalendar()
+	refreshKanban()
+	updateGroupDropdown()
+	if len(groups) > 0 {
+		sbGroupSelect.SetSelected(groups[0].Name)
+	}
+	resetSidebar()
+}
+
+// showCalendarSwitcher opens a filterable calendar list (Ctrl/Cmd+K); Enter picks the first match.
+func showCalendarSwitcher() {
+	var d dialog.Dialog
+	all := append(calendarNames(), allCalendarsName)
+	matches := all
+	pick := func(name string) {
+		d.Hide()
+		if name != activeCalendarName {
+			confirmDiscardSidebar(func() { switchCalendar(name) })
+		}
+	}
+	list := widget.NewList(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			label := calendarLabel(matches[id])
+			if matches[id] == activeCalendarName {
+				label += "  (active)"
+			}
+			o.(*widget.Label).SetText(label)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { pick(matches[id]) }
+	filter := widget.NewEntry()
+	filter.PlaceHolder = "Type to filter calendars..."
+	filter.OnChanged = func(s string) {
+		q := strings.ToLower(strings.TrimSpace(s))
+		matches = nil
+		for _, name := range all {
+			if strings.Contains(strings.ToLower(calendarLabel(name)), q) {
+				matches = append(matches, name)
+			}
+		}
+		list.UnselectAll()
+		list.Refresh()
+	}
+	filter.OnSubmitted = func(string) {
+		if len(matches) > 0 {
+			pick(matches[0])
+		}
+	}
+	d = dialog.NewCustom("Switch Calendar", "Cancel", container.NewBorder(filter, nil, nil, nil, list), mainWindow)
+	d.Resize(fyne.NewSize(360, 420))
+	d.Show()
+	mainWindow.Canvas().Focus(filter)
+}
+func renameCalendar(oldName, newName string) error {
+	if newName == "" || newName == oldName {
+		return nil
+	}
+	if newName == allCalendarsName {
+		return fmt.Errorf("'%s' is reserved", allCalendarsName)
+	}
+	if calendarIndex(newName) >= 0 {
+		return fmt.Errorf("a calendar named '%s' already exists", newName)
+	}
+	oldData, oldGroups := calendarFilenames(oldName)
+	newData, newGroups := calendarFilenames(newName)
+	for _, f := range []string{newData, newGroups} {
+		if _, err := os.Stat(f); err == nil && f != oldData && f != oldGroups {
+			return fmt.Errorf("data files for '%s' already exist", newName)
+		}
+	}
+	if oldName == activeCalendarName {
+		// A save still pending would otherwise recreate the old file after the rename.
+		flushPendingSave()
+	}
+	for _, pair := range [][2]string{{oldData, newData}, {oldGroups, newGroups}, {oldData + ".bak", newData + ".bak"}, {oldGroups + ".bak", newGroups + ".bak"}} {
+		if err := os.Rename(pair[0], pair[1]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if data, ok := ownWrites[pair[0]]; ok {
+			delete(ownWrites, pair[0])
+			ownWrites[pair[1]] = data
+		}
+	}
+	if at, ok := lastBackupAt[oldData]; ok {
+		delete(lastBackupAt, oldData)
+		lastBackupAt[newData] = at
+	}
+	backupDir := filepath.Join(filepath.Dir(oldData), "backups")
+	for _, snap := range listBackups(oldData) {
+		stamp := strings.TrimPrefix(snap, backupPrefix(oldData))
+		_ = os.Rename(filepath.Join(backupDir, snap), filepath.Join(backupDir, backupPrefix(newData)+stamp))
+	}
+	availableCalendars[calendarIndex(oldName)].Name = newName
+	saveCalendarList()
+	for _, sets := range []map[string][]string{appSettings.HiddenGroups, appSettings.CollapsedGroups} {
+		if ids, ok := sets[oldName]; ok {
+			delete(sets, oldName)
+			sets[newName] = ids
+		}
+	}
+	if acct, ok := appSettings.CalDAV[oldName]; ok {
+		delete(appSettings.CalDAV, oldName)
+		appSettings.CalDAV[newName] = acct
+	}
+	saveSettings()
+	if activeCalendarName == oldName {
+		activeCalendarName = newName
+		updateWindowTitle()
+		startFileWatch()
+	}
+	return nil
+}
+
+// duplicateCalendar copies a calendar's groups and items under a new name. Items get fresh IDs (series links are
+// remapped together); group IDs are scoped to their calendar file so they are kept as-is.
+func duplicateCalendar(srcName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("enter a name for the copy")
+	}
+	if newName == allCalendarsName {
+		return fmt.Errorf("'%s' is reserved", allCalendarsName)
+	}
+	if calendarIndex(newName) >= 0 {
+		return fmt.Errorf("a calendar named '%s' already exists", newName)
+	}
+	if srcName == activeCalendarName {
+		flushPendingSave()
+	}
+	srcData, srcGroups := calendarFilenames(srcName)
+	var calGroups []Group
+	var calItems []TodoItem
+	if err := readJSONFile(srcGroups, &calGroups); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	calItems, _, err := readItemsFile(srcData, calGroups)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	stamp := time.Now().UnixNano()
+	seriesIDs := make(map[string]string)
+	for i := range calItems {
+		calItems[i].ID = fmt.Sprintf("%d-%d", stamp, i)
+		if sid := calItems[i].SeriesID; sid != "" {
+			if _, ok := seriesIDs[sid]; !ok {
+				seriesIDs[sid] = fmt.Sprintf("s-%d-%d", stamp, len(seriesIDs))
+			}
+			calItems[i].SeriesID = seriesIDs[sid]
+		}
+	}
+	newData, newGroups := calendarFilenames(newName)
+	groupJSON, _ := json.MarshalIndent(calGroups, "", " ")
+	if err := writeFileAtomic(newGroups, groupJSON); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(newData, marshalItems(calItems)); err != nil {
+		return err
+	}
+	meta := CalendarMeta{Name: newName}
+	if i := calendarIndex(srcName); i >= 0 {
+		meta.ColorHex, meta.Icon = availableCalendars[i].ColorHex, availableCalendars[i].Icon
+	}
+	availableCalendars = append(availableCalendars, meta)
+	saveCalendarList()
+	for _, sets := range []map[string][]string{appSettings.HiddenGroups, appSettings.CollapsedGroups} {
+		if ids, ok := sets[srcName]; ok {
+			sets[newName] = slices.Clone(ids)
+		}
+	}
+	saveSettings()
+	return nil
+}
+
+// loadAllCalendars merges every calendar into memory, prefixing group IDs and names with the source calendar so they can't collide.
+func loadAllCalendars() {
+	for _, cal := range calendarNames() {
+		dataFile, groupFile := calendarFilenames(cal)
+		var calGroups []Group
+		if err := readJSONFile(groupFile, &calGroups); err != nil && !os.IsNotExist(err) {
+			dialog.ShowError(err, mainWindow)
+		}
+		calItems, _, err := readItemsFile(dataFile, calGroups)
+		if err != nil && !os.IsNotExist(err) {
+			dialog.ShowError(err, mainWindow)
+		}
+		for _, g := range calGroups {
+			g.ID = cal + "/" + g.ID
+			g.Name = cal + " / " + g.Name
+			groups = append(groups, g)
+		}
+		for _, item := range calItems {
+			ids := itemGroupIDs(&item)
+			for i := range ids {
+				if ids[i] != "" {
+					ids[i] = cal + "/" + ids[i]
+				}
+			}
+			setItemGroups(&item, ids)
+			item.Calendar = cal
+			items = append(items, item)
+		}
+	}
+	seedFiredReminders()
+	validateItemDates()
+}
+func blockReadOnly() bool {
+	if activeCalendarName != allCalendarsName {
+		return false
+	}
+	dialog.ShowInformation("Read-Only", "The All Calendars view is read-only. Switch to a single calendar to make changes.", mainWindow)
+	return true
+}
+func showCalendarManager() {
+	var d dialog.Dialog
+	input := widget.NewEntry()
+	input.PlaceHolder = "New Calendar Name"
+	list := widget.NewList(
+		func() int { return len(availableCalendars) },
+		func() fyne.CanvasObject {
+			swatch := canvas.NewRectangle(color.Transparent)
+			swatch.SetMinSize(fyne.NewSize(14, 14))
+			return container.NewHBox(container.NewCenter(swatch), widget.NewLabel("Name"), layout.NewSpacer(), widget.NewButtonWithIcon("", theme.ColorPaletteIcon(), nil), widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil), widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil), widget.NewButtonWithIcon("", theme.DeleteIcon(), nil))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			box := o.(*fyne.Container)
+			swatch := box.Objects[0].(*fyne.Container).Objects[0].(*canvas.Rectangle)
+			lbl := box.Objects[1].(*widget.Label)
+			styleBtn := box.Objects[3].(*widget.Button)
+			copyBtn := box.Objects[4].(*widget.Button)
+			renameBtn := box.Objects[5].(*widget.Button)
+			btn := box.Objects[6].(*widget.Button)
+			name := availableCalendars[i].Name
+			lbl.SetText(calendarLabel(name))
+			swatch.FillColor = calendarColor(name)
+			swatch.Refresh()
+			styleBtn.OnTapped = func() {
+				showCalendarStyleDialog(name, func() {
+					d.Hide()
+					showCalendarManager()
+				})
+			}
+			copyBtn.OnTapped = func() {
+				entry := widget.NewEntry()
+				entry.SetText(name + " Copy")
+				dialog.ShowForm("Duplicate Calendar", "Duplicate", "Cancel", []*widget.FormItem{widget.NewFormItem("New Name", entry)}, func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := duplicateCalendar(name, strings.TrimSpace(entry.Text)); err != nil {
+						dialog.ShowError(err, mainWindow)
+						return
+					}
+					d.Hide()
+					showCalendarManager()
+				}, mainWindow)
+			}
+			renameBtn.OnTapped = func() {
+				entry := widget.NewEntry()
+				entry.SetText(name)
+				dialog.ShowForm("Rename Calendar", "Rename", "Cancel", []*widget.FormItem{widget.NewFormItem("New Name", entry)}, func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := renameCalendar(name, strings.TrimSpace(entry.Text)); err != nil {
+						dialog.ShowError(err, mainWindow)
+						return
+					}
+					d.Hide()
+					showCalendarManager()
+				}, mainWindow)
+			}
+			btn.OnTapped = func() {
+				if len(availableCalendars) <= 1 {
+					dialog.ShowError(fmt.Errorf("cannot delete last"), mainWindow)
+					return
+				}
+				dialog.ShowConfirm("Delete", "Delete '"+name+"'?", func(ok bool) {
+					if ok {
+						availableCalendars = slices.DeleteFunc(availableCalendars, func(c CalendarMeta) bool { return c.Name == name })
+						saveCalendarList()
+						if _, ok := appSettings.CalDAV[name]; ok {
+							delete(appSettings.CalDAV, name)
+							saveSettings()
+						}
+						if activeCalendarName == name {
+							switchCalendar(availableCalendars[0].Name)
+						}
+						d.Hide()
+						showCalendarManager()
+					}
+				}, mainWindow)
+			}
+		},
+	)
+	createBtn := widget.NewButton("Create", func() {
+		if input.Text == "" {
+			return
+		}
+		if input.Text == allCalendarsName {
+			dialog.ShowError(fmt.Errorf("'%s' is reserved", allCalendarsName), mainWindow)
+			return
+		}
+		if calendarIndex(input.Text) >= 0 {
+			return
+		}
+		availableCalendars = append(availableCalendars, CalendarMeta{Name: input.Text})
+		saveCalendarList()
+		switchCalendar(input.Text)
+		d.Hide()
+	})
+	restoreBtn := widget.NewButtonWithIcon("Restore from Backup", theme.HistoryIcon(), func() { d.Hide(); showBackupRestore() })
+	d = dialog.NewCustom("Manage Calendars", "Close", container.NewPadded(container.NewBorder(container.NewVBox(widget.NewLabel("Create New:"), container.NewBorder(nil, nil, nil, createBtn, input), widget.NewSeparator()), restoreBtn, nil, nil, list)), mainWindow)
+	d.Resize(fyne.NewSize(440, 500))
+	d.Show()
+}
+
+// showCalendarStyleDialog edits a calendar's color and icon; an empty icon or "No Color" clears them.
+func showCalendarStyleDialog(name string, done func()) {
+	i := calendarIndex(name)
+	if i < 0 {
+		return
+	}
+	var d dialog.Dialog
+	iconEntry := widget.NewEntry()
+	iconEntry.PlaceHolder = "Emoji or short text, e.g. 💼"
+	iconEntry.SetText(availableCalendars[i].Icon)
+	apply := func(hex string) {
+		availableCalendars[i].ColorHex = hex
+		availableCalendars[i].Icon = strings.TrimSpace(iconEntry.Text)
+		saveCalendarList()
+		updateWindowTitle()
+		d.Hide()
+		done()
+	}
+	grid := colorSwatchGrid(apply)
+	keepBtn := widget.NewButton("Save Icon Only", func() { apply(availableCalendars[i].ColorHex) })
+	noColorBtn := widget.NewButton("No Color", func() { apply("") })
+	content := container.NewVBox(widget.NewLabel("Icon"), iconEntry, widget.NewLabel("Color"), grid, container.NewGridWithColumns(2, keepBtn, noColorBtn))
+	d = dialog.NewCustom("Calendar Style: "+name, "Cancel", content, mainWindow)
+	d.Show()
+}
+func showGroupManager() {
+	if blockReadOnly() {
+		return
+	}
+	var d dialog.Dialog
+	listContainer := container.NewVBox()
+	for _, i := range columnOrder() {
+		grp := groups[i]
+		colorRect := canvas.NewRectangle(parseHexColor(grp.ColorHex))
+		colorRect.SetMinSize(fyne.NewSize(20, 20))
+		lbl := widget.NewLabel(grp.Name)
+		btnEdit := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() { d.Hide(); showGroupForm(&grp) })
+		removeGroup := func() {
+			newGroups := []Group{}
+			for _, g := range groups {
+				if g.ID != grp.ID {
+					newGroups = append(newGroups, g)
+				}
+			}
+			for i := range newGroups {
+				if newGroups[i].ParentID == grp.ID {
+					newGroups[i].ParentID = ""
+				}
+			}
+			groups = newGroups
+			saveGroups()
+			updateGroupDropdown()
+			if sbGroupSelect.Selected == grp.Name {
+				if len(groups) > 0 {
+					sbGroupSelect.SetSelected(groups[0].Name)
+				} else {
+					sbGroupSelect.SetSelected("")
+				}
+			}
+			refreshCalendar()
+			refreshKanban()
+			d.Hide()
+			showGroupManager()
+		}
+		btnDel := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			inGroup, onlyHere := 0, 0
+			for _, it := range items {
+				if ids := itemGroupIDs(&it); slices.Contains(ids, grp.ID) {
+					inGroup++
+					if len(ids) == 1 {
+						onlyHere++
+					}
+				}
+			}
+			if inGroup == 0 {
+				dialog.ShowConfirm("Delete Group", "Delete '"+grp.Name+"'?", func(ok bool) {
+					if ok {
+						removeGroup()
+					}
+				}, mainWindow)
+				return
+			}
+			showDeleteGroupWithItems(grp, onlyHere, removeGroup)
+		})
+		btnMerge := widget.NewButtonWithIcon("", theme.MailForwardIcon(), func() { showMergeGroupDialog(grp, removeGroup) })
+		btnUp := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { moveGroupColumn(grp.ID, -1); d.Hide(); showGroupManager() })
+		btnDown := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { moveGroupColumn(grp.ID, 1); d.Hide(); showGroupManager() })
+		var left fyne.CanvasObject = colorRect
+		if groupParent(grp) != nil {
+			indent := canvas.NewRectangle(color.Transparent)
+			indent.SetMinSize(fyne.NewSize(20, 0))
+			left = container.NewHBox(indent, colorRect)
+		}
+		listContainer.Add(container.NewBorder(nil, nil, left, container.NewHBox(btnUp, btnDown, btnMerge, btnEdit, btnDel), lbl))
+	}
+	scroll := container.NewVScroll(listContainer)
+	scroll.SetMinSize(fyne.NewSize(300, 400))
+	d = dialog.NewCustom("Groups", "Close", scroll, mainWindow)
+	d.Resize(fyne.NewSize(350, 500))
+	d.Show()
+}
+func showGroupForm(existingGroup *Group) {
+	var d dialog.Dialog
+	isEdit := existingGroup != nil
+	nameEntry := widget.NewEntry()
+	nameEntry.PlaceHolder = "e.g. Work"
+	defaultColor := PresetColors[0]
+	if isEdit {
+		nameEntry.SetText(existingGroup.Name)
+		defaultColor = existingGroup.ColorHex
+	}
+	selectedColor := defaultColor
+	previewRect := canvas.NewRectangle(parseHexColor(selectedColor))
+	previewRect.SetMinSize(fyne.NewSize(50, 20))
+	hexEntry := widget.NewEntry()
+	hexEntry.PlaceHolder = "#RRGGBB"
+	hexEntry.Validator = func(s string) error {
+		if _, ok := normalizeHexColor(s); !ok {
+			return fmt.Errorf("use a hex color like #1A2B3C")
+		}
+		return nil
+	}
+	hexEntry.OnChanged = func(s string) {
+		if hex, ok := normalizeHexColor(s); ok {
+			selectedColor = hex
+			previewRect.FillColor = parseHexColor(hex)
+			previewRect.Refresh()
+		}
+	}
+	hexEntry.Hide()
+	wipEntry := widget.NewEntry()
+	wipEntry.PlaceHolder = "No limit"
+	wipEntry.Validator = func(s string) error {
+		if n, err := strconv.Atoi(strings.TrimSpace(s)); s != "" && (err != nil || n < 0) {
+			return fmt.Errorf("enter a whole number")
+		}
+		return nil
+	}
+	if isEdit && existingGroup.WipLimit > 0 {
+		wipEntry.SetText(strconv.Itoa(existingGroup.WipLimit))
+	}
+	const noParent = "(None)"
+	parentOptions := []string{noParent}
+	parentIDs := map[string]string{}
+	for _, i := range columnOrder() {
+		g := groups[i]
+		if groupParent(g) != nil || (isEdit && g.ID == existingGroup.ID) {
+			continue
+		}
+		parentOptions = append(parentOptions, g.Name)
+		parentIDs[g.Name] = g.ID
+	}
+	parentSelect := widget.NewSelect(parentOptions, nil)
+	parentSelect.SetSelected(noParent)
+	if isEdit {
+		if p := groupParent(*existingGroup); p != nil {
+			parentSelect.SetSelected(p.Name)
+		}
+		if hasSubGroups(existingGroup.ID) {
+			parentSelect.Disable()
+		}
+	}
+	colorGrid := colorSwatchGrid(func(hex string) {
+		selectedColor = hex
+		previewRect.FillColor = parseHexColor(hex)
+		previewRect.Refresh()
+		hexEntry.Hide()
+	})
+	customBtn := widget.NewButton("Custom…", func() {
+		hexEntry.SetText(selectedColor)
+		hexEntry.Show()
+		mainWindow.Canvas().Focus(hexEntry)
+	})
+	btnLabel := "Create Group"
+	if isEdit {
+		btnLabel = "Save Changes"
+	}
+	actionBtn := widget.NewButton(btnLabel, func() {
+		if nameEntry.Text == "" || wipEntry.Validate() != nil {
+			return
+		}
+		wipLimit, _ := strconv.Atoi(strings.TrimSpace(wipEntry.Text))
+		rememberCustomColor(selectedColor)
+		targetName := nameEntry.Text
+		if isEdit {
+			for i, g := range groups {
+				if g.ID == existingGroup.ID {
+					groups[i].Name = nameEntry.Text
+					groups[i].ColorHex = selectedColor
+					groups[i].WipLimit = wipLimit
+					groups[i].ParentID = parentIDs[parentSelect.Selected]
+					break
+				}
+			}
+		} else {
+			newGroup := Group{ID: fmt.Sprintf("g-%d", time.Now().UnixNano()), Name: nameEntry.Text, ColorHex: selectedColor, WipLimit: wipLimit, ParentID: parentIDs[parentSelect.Selected], SortOrder: nextGroupSortOrder()}
+			groups = append(groups, newGroup)
+			targetName = newGroup.Name
+		}
+		saveGroups()
+		updateGroupDropdown()
+		refreshCalendar()
+		refreshKanban()
+		sbGroupSelect.SetSelected(targetName)
+		if d != nil {
+			d.Hide()
+		}
+	})
+	content := container.NewVBox(widget.NewLabel("Group Name:"), nameEntry, widget.NewLabel("Group Color:"), previewRect, colorGrid, customBtn, hexEntry, widget.NewLabel("WIP Limit (open items):"), wipEntry, widget.NewLabel("Parent Group:"), parentSelect, layout.NewSpacer(), actionBtn)
+	d = dialog.NewCustom("Group", "Cancel", container.NewPadded(content), mainWindow)
+	d.Resize(fyne.NewSize(300, 520))
+	d.Show()
+}
+
+// colorSwatchGrid lays out the preset and remembered custom colors as clickable swatches.
+func colorSwatchGrid(onPick func(hex string)) *fyne.Container {
+	colorGrid := container.NewGridWithColumns(6)
+	for _, c := range append(slices.Clone(PresetColors), appSettings.CustomColors...) {
+		hex := c
+		rect := canvas.NewRectangle(parseHexColor(hex))
+		rect.SetMinSize(fyne.NewSize(30, 30))
+		rect.StrokeColor = color.White
+		rect.StrokeWidth = 1
+		colorGrid.Add(newClickableBox(container.NewStack(rect), func() { onPick(hex) }))
+	}
+	return colorGrid
+}
+
+// itemColor is the item's own color when it has one, otherwise its primary group's.
+func itemColor(item *TodoItem, groupColors map[string]color.Color) color.Color {
+	if item.ColorHex != "" {
+		return parseHexColor(item.ColorHex)
+	}
+	if c, ok := groupColors[item.GroupID]; ok {
+		return c
+	}
+	return color.Gray{Y: 100}
+}
+func setSidebarColor(hex string) {
+	sbColorHex = hex
+	if sbColorRect == nil {
+		return
+	}
+	sbColorRect.FillColor = color.Transparent
+	if hex != "" {
+		sbColorRect.FillColor = parseHexColor(hex)
+	}
+	sbColorRect.Refresh()
+}
+func showItemColorPicker() {
+	var d dialog.Dialog
+	grid := colorSwatchGrid(func(hex string) {
+		setSidebarColor(hex)
+		autoSave()
+		d.Hide()
+	})
+	resetBtn := widget.NewButton("Use Group Color", func() {
+		setSidebarColor("")
+		autoSave()
+		d.Hide()
+	})
+	d = dialog.NewCustom("Item Color", "Cancel", container.NewVBox(grid, resetBtn), mainWindow)
+	d.Show()
+}
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+func shiftItemDays(item *TodoItem, days int) {
+	s, _ := parseItemTime(item.Start)
+	e, _ := parseItemTime(item.End)
+	item.Start = s.AddDate(0, 0, days).Format("2006-01-02 15:04")
+	item.End = e.AddDate(0, 0, days).Format("2006-01-02 15:04")
+}
+func chooseSeriesScope(targetItem *TodoItem, title, prompt string, apply func([]*TodoItem)) {
+	if targetItem.SeriesID == "" {
+		apply([]*TodoItem{targetItem})
+		return
+	}
+	var d dialog.Dialog
+	targetID, seriesID := targetItem.ID, targetItem.SeriesID
+	targetTime, _ := parseItemTime(targetItem.Start)
+	run := func(includeFuture, includePast bool) {
+		targets := []*TodoItem{}
+		for i := range items {
+			if items[i].SeriesID != seriesID {
+				continue
+			}
+			iTime, _ := parseItemTime(items[i].Start)
+			if items[i].ID == targetID || (includeFuture && !iTime.Before(targetTime)) || (includePast && iTime.Before(targetTime)) {
+				targets = append(targets, &items[i])
+			}
+		}
+		d.Hide()
+		apply(targets)
+	}
+	d = dialog.NewCustom(title, "Cancel", container.NewVBox(widget.NewLabel(prompt),
+		widget.NewButton("This Only", func() { run(false, false) }),
+		widget.NewButton("This + Future", func() { run(true, false) }),
+		widget.NewButton("All", func() { run(true, true) }),
+	), mainWindow)
+	d.Show()
+}
+
+// skipOccurrence removes one occurrence of a series and records its start in every remaining occurrence's
+// Exceptions, so imports and syncs don't bring it back and exports write it as an EXDATE.
+func skipOccurrence(item *TodoItem) {
+	if blockReadOnly() || item.SeriesID == "" {
+		return
+	}
+	s, _ := parseItemTime(item.Start)
+	msg := fmt.Sprintf("Skip '%s' on %s? The rest of the series stays.", item.Title, formatDate(s, "Mon, Jan 2"))
+	dialog.ShowConfirm("Skip Occurrence", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		id, seriesID, start := item.ID, item.SeriesID, item.Start
+		if id == currentEditItemID {
+			resetSidebar()
+		}
+		items = slices.DeleteFunc(items, func(it TodoItem) bool { return it.ID == id })
+		for i := range items {
+			if items[i].SeriesID == seriesID && !slices.Contains(items[i].Exceptions, start) {
+				items[i].Exceptions = append(slices.Clone(items[i].Exceptions), start)
+				sort.Strings(items[i].Exceptions)
+			}
+		}
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+	}, mainWindow)
+}
+
+// isSkippedOccurrence reports whether an incoming item with the given source UID matches an occurrence that was
+// skipped in an existing series. A series is known by the UID it was imported from, or by its own ID once exported.
+func isSkippedOccurrence(p TodoItem, uid string) bool {
+	if uid == "" {
+		return false
+	}
+	return slices.ContainsFunc(items, func(it TodoItem) bool {
+		return it.SeriesID != "" && (it.SourceUID == uid || it.SeriesID == uid) && slices.Contains(it.Exceptions, p.Start)
+	})
+}
+func performSmartDelete(targetID string) {
+	var targetItem *TodoItem
+	for i := range items {
+		if items[i].ID == targetID {
+			targetItem = &items[i]
+			break
+		}
+	}
+	if targetItem == nil {
+		return
+	}
+	var d dialog.Dialog
+	finish := func() {
+		saveData()
+		refreshCalendar()
+		refreshKanban()
+		resetSidebar()
+		if d != nil {
+			d.Hide()
+		}
+	}
+	if targetItem.SeriesID == "" {
+		dialog.ShowConfirm("Delete", "Delete?", func(ok bool) {
+			if ok {
+				newItems := []TodoItem{}
+				for _, i := range items {
+					if i.ID != targetID {
+						newItems = append(newItems, i)
+					}
+				}
+				items = newItems
+				finish()
+			}
+		}, mainWindow)
+		return
+	}
+	d = dialog.NewCustom("Delete Recurring", "Cancel", container.NewVBox(widget.NewLabel("Repeating item. Delete?"), widget.NewButton("This Only", func() {
+		newItems := []TodoItem{}
+		for _, i := range items {
+			if i.ID != targetID {
+				newItems = append(newItems, i)
+			}
+		}
+		items = newItems
+		finish()
+	}), widget.NewButton("This + Future", func() {
+		targetTime, _ := parseItemTime(targetItem.Start)
+		newItems := []TodoItem{}
+		for _, i := range items {
+			iTime, _ := parseItemTime(i.Start)
+			if i.SeriesID != targetItem.SeriesID || iTime.Before(targetTime) {
+				newItems = append(newItems, i)
+			}
+		}
+		items = newItems
+		finish()
+	}), widget.NewButton("All", func() {
+		newItems := []TodoItem{}
+		for _, i := range items {
+			if i.SeriesID != targetItem.SeriesID {
+				newItems = append(newItems, i)
+			}
+		}
+		items = newItems
+		finish()
+	})), mainWindow)
+	d.Show()
+}
+func importICS() {
+	if blockReadOnly() {
+		return
+	}
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, _ := io.ReadAll(reader)
+		parsed, uids, skipped, err := parseICSItems(data)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to parse %s: %w", reader.URI().Name(), err), mainWindow)
+			return
+		}
+		chooseImportMode(parsed, uids, skipped)
+	}, mainWindow)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".ics"}))
+	fd.Show()
+}
+
+// normalizeICS strips a BOM, normalizes CR/LF/CRLF line endings and unfolds continuation lines, checking each
+// logical line and the BEGIN/END nesting so a bad file reports the physical line that broke it.
+func normalizeICS(data []byte) (string, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	var lines []string
+	var lineNos []int
+	for n, l := range strings.Split(text, "\n") {
+		switch {
+		case (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0:
+			lines[len(lines)-1] += l[1:]
+		case strings.TrimSpace(l) != "":
+			lines = append(lines, l)
+			lineNos = append(lineNos, n+1)
+		}
+	}
+	var open []string
+	for i, l := range lines {
+		p, err := ical.ParseProperty(ical.ContentLine(l))
+		if err == nil && p == nil {
+			err = errors.New("not a property line")
+		}
+		if err != nil {
+			if len(l) > 60 {
+				l = l[:60] + "…"
+			}
+			return "", fmt.Errorf("line %d: %w (%q)", lineNos[i], err, l)
+		}
+		switch p.IANAToken {
+		case "BEGIN":
+			open = append(open, strings.ToUpper(p.Value))
+		case "END":
+			if len(open) == 0 || open[len(open)-1] != strings.ToUpper(p.Value) {
+				return "", fmt.Errorf("line %d: unexpected END:%s", lineNos[i], p.Value)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(lines) == 0 {
+		return "", errors.New("file is empty")
+	}
+	if len(open) > 0 {
+		return "", fmt.Errorf("missing END:%s at end of file", open[len(open)-1])
+	}
+	return strings.Join(lines, "\r\n") + "\r\n", nil
+}
+
+// uids maps each parsed item ID to the UID of the VEVENT it came from; skipped counts events whose
+// DTSTART/DTEND couldn't be read, which are left out rather than landing in year 0001.
+func parseICSItems(data []byte) (parsed []TodoItem, uids map[string]string, skipped int, err error) {
+	text, err := normalizeICS(data)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	parsedCal, err := ical.ParseCalendar(strings.NewReader(text))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	uids = make(map[string]string)
+	// overrides holds RECURRENCE-ID events by UID and occurrence start; they replace that occurrence of the series.
+	overrides := make(map[[2]string]TodoItem)
+	count := 0
+	targetGroupID := ""
+	if len(groups) > 0 {
+		targetGroupID = groups[0].ID
+	}
+	for _, event := range parsedCal.Events() {
+		sum := event.GetProperty(ical.ComponentPropertySummary)
+		start := event.GetProperty(ical.ComponentPropertyDtStart)
+		end := event.GetProperty(ical.ComponentPropertyDtEnd)
+		if sum == nil || start == nil {
+			continue
+		}
+		title := sum.Value
+		allDay := len(start.Value) == 8
+		if v, ok := start.ICalParameters[string(ical.ParameterValue)]; ok && len(v) > 0 && strings.EqualFold(v[0], "DATE") {
+			allDay = true
+		}
+		var sTime, eTime time.Time
+		var timeErr error
+		iType := TypeTask
+		if allDay {
+			sTime, timeErr = time.ParseInLocation("20060102", start.Value[:min(8, len(start.Value))], time.Local)
+			eTime = sTime
+			if end != nil && timeErr == nil {
+				var t time.Time
+				if t, timeErr = time.ParseInLocation("20060102", end.Value[:min(8, len(end.Value))], time.Local); timeErr == nil && t.After(sTime) {
+					eTime = t.AddDate(0, 0, -1)
+				}
+			}
+			eTime = eTime.Add(23*time.Hour + 59*time.Minute)
+			iType = TypeEvent
+		} else {
+			sTime, timeErr = parseICSTime(start.Value, start.ICalParameters)
+			eTime = sTime
+			if end != nil && timeErr == nil {
+				eTime, timeErr = parseICSTime(end.Value, end.ICalParameters)
+			}
+			if !eTime.Equal(sTime) {
+				iType = TypeEvent
+			}
+		}
+		if timeErr != nil {
+			skipped++
+			continue
+		}
+		newItem := TodoItem{ID: fmt.Sprintf("imp-%d-%d", time.Now().UnixNano(), count), Title: title, Start: sTime.Format("2006-01-02 15:04"), End: eTime.Format("2006-01-02 15:04"), Type: iType, GroupID: targetGroupID, AllDay: allDay, CreatedAt: time.Now().Format("2006-01-02 15:04")}
+		for _, cat := range event.GetProperties(ical.ComponentPropertyCategories) {
+			newItem.Tags = append(newItem.Tags, parseTags(cat.Value)...)
+		}
+		if gs := event.GetProperties(icsPropGroup); len(gs) > 0 && gs[0].Value != "" {
+			newItem.GroupName = gs[0].Value
+			for _, g := range gs[1:] {
+				newItem.ImportGroups = append(newItem.ImportGroups, g.Value)
+			}
+		} else if rest, ok := strings.CutPrefix(title, "["); ok {
+			// Older exports flattened the group into "[Group] Title".
+			if name, t, ok := strings.Cut(rest, "] "); ok && slices.ContainsFunc(groups, func(g Group) bool { return g.Name == name }) {
+				newItem.GroupName = name
+				newItem.Title = t
+			}
+		}
+		if c := event.GetProperty(icsPropCompleted); c != nil && strings.EqualFold(c.Value, "TRUE") {
+			newItem.Completed = true
+		}
+		if t := event.GetProperty(ical.ComponentPropertyTransp); t != nil && strings.EqualFold(t.Value, "TRANSPARENT") && newItem.Type == TypeEvent {
+			newItem.Availability = AvailabilityFree
+		}
+		for _, a := range event.GetProperties(ical.ComponentPropertyAttach) {
+			if _, inline := a.ICalParameters[string(ical.ParameterEncoding)]; inline {
+				continue
+			}
+			if u, err := url.Parse(a.Value); err == nil && u.Scheme == "file" {
+				newItem.Attachments = append(newItem.Attachments, u.Path)
+			} else if a.Value != "" {
+				newItem.Attachments = append(newItem.Attachments, a.Value)
+			}
+		}
+		if rid := event.GetProperty(ical.ComponentPropertyRecurrenceId); rid != nil {
+			if t, err := parseICSTime(rid.Value, rid.ICalParameters); err == nil {
+				overrides[[2]string{event.Id(), t.Format("2006-01-02 15:04")}] = newItem
+				count++
+				continue
+			}
+		}
+		rrule := event.GetProperty(ical.ComponentPropertyRrule)
+		if rrule == nil {
+			parsed = append(parsed, newItem)
+			uids[newItem.ID] = event.Id()
+			count++
+			continue
+		}
+		rule, ok := parseRRule(rrule.Value)
+		if !ok {
+			parsed = append(parsed, newItem)
+			uids[newItem.ID] = event.Id()
+			count++
+			continue
+		}
+		excluded := make(map[string]bool)
+		for _, ex := range event.GetProperties(ical.ComponentPropertyExdate) {
+			for _, v := range strings.Split(ex.Value, ",") {
+				if t, err := parseICSTime(v, ex.ICalParameters); err == nil {
+					excluded[t.Format("2006-01-02 15:04")] = true
+				}
+			}
+		}
+		newItem.SeriesID = fmt.Sprintf("s-%d-%d", time.Now().UnixNano(), count)
+		newItem.SourceUID = event.Id()
+		for start := range excluded {
+			newItem.Exceptions = append(newItem.Exceptions, start)
+		}
+		sort.Strings(newItem.Exceptions)
+		for _, occ := range append([]TodoItem{newItem}, generateOccurrences(newItem, rule)...) {
+			if excluded[occ.Start] {
+				continue
+			}
+			parsed = append(parsed, occ)
+			uids[occ.ID] = event.Id()
+			count++
+		}
+	}
+	for i, p := range parsed {
+		key := [2]string{uids[p.ID], p.Start}
+		ov, ok := overrides[key]
+		if !ok || p.SeriesID == "" {
+			continue
+		}
+		ov.ID, ov.SeriesID, ov.SourceUID, ov.Exceptions = p.ID, p.SeriesID, p.SourceUID, p.Exceptions
+		parsed[i] = ov
+		delete(overrides, key)
+	}
+	// Overrides whose series wasn't in the file (or whose occurrence was cut) still come in as single items.
+	for key, ov := range overrides {
+		parsed = append(parsed, ov)
+		uids[ov.ID] = key[0]
+	}
+	return parsed, uids, skipped, nil
+}
+
+// chooseImportMode previews the parsed items with checkboxes; only the checked ones are merged in or replace the calendar.
+// unreadable is how many entries the parser had to drop, repeated in the summary.
+func chooseImportMode(parsed []TodoItem, uids map[string]string, unreadable int) {
+	var d dialog.Dialog
+	checked := make([]bool, len(parsed))
+	for i := range checked {
+		checked[i] = true
+	}
+	countLabel := widget.NewLabel("")
+	updateCount := func() {
+		n := 0
+		for _, c := range checked {
+			if c {
+				n++
+			}
+		}
+		countLabel.SetText(fmt.Sprintf("%d of %d items selected for '%s'.", n, len(parsed), activeCalendarName))
+	}
+	updateCount()
+	list := widget.NewList(
+		func() int { return len(parsed) },
+		func() fyne.CanvasObject { return widget.NewCheck("", nil) },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			p := parsed[id]
+			s, _ := parseItemTime(p.Start)
+			when := formatDate(s, "Mon, Jan 2 2006")
+			if !p.AllDay {
+				when += " " + formatClock(s)
+			}
+			check := o.(*widget.Check)
+			check.OnChanged = nil
+			check.Text = fmt.Sprintf("%s · %s · %s%s", when, p.Type, seriesMark(&p), p.Title)
+			check.Checked = checked[id]
+			check.Refresh()
+			check.OnChanged = func(b bool) { checked[id] = b; updateCount() }
+		},
+	)
+	setAll := func(b bool) {
+		for i := range checked {
+			checked[i] = b
+		}
+		list.Refresh()
+		updateCount()
+	}
+	finish := func(replace bool) {
+		d.Hide()
+		if replace {
+			items = []TodoItem{}
+			resetSidebar()
+		}
+		added, selected := 0, 0
+		for i, p := range parsed {
+			if !checked[i] {
+				continue
+			}
+			selected++
+			duplicate := false
+			for _, existing := range items {
+				if (uids[p.ID] != "" && existing.ID == uids[p.ID]) || (existing.Title == p.Title && existing.Start == p.Start) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate || isSkippedOccurrence(p, uids[p.ID]) {
+				continue
+			}
+			resolveImportGroups(&p)
+			items = append(items, p)
+			added++
+		}
+		saveData()
+		updateGroupDropdown()
+		refreshCalendar()
+		refreshKanban()
+		msg := fmt.Sprintf("%d items (%d duplicates skipped)", added, selected-added)
+		if unreadable > 0 {
+			msg += fmt.Sprintf("\n%d entries couldn't be read (missing title or bad start/end) and were skipped", unreadable)
+		}
+		dialog.ShowInformation("Imported", msg, mainWindow)
+	}
+	actions := container.NewVBox(
+		container.NewGridWithColumns(2, widget.NewButton("Select All", func() { setAll(true) }), widget.NewButton("Select None", func() { setAll(false) })),
+		widget.NewButton("Merge", func() { finish(false) }),
+		widget.NewButton("Replace", func() {
+			dialog.ShowConfirm("Replace", "Remove every item in '"+activeCalendarName+"' before importing?", func(ok bool) {
+				if ok {
+					finish(true)
+				}
+			}, mainWindow)
+		}),
+	)
+	d = dialog.NewCustom("Import", "Cancel", container.NewBorder(countLabel, actions, nil, nil, list), mainWindow)
+	d.Resize(fyne.NewSize(520, 520))
+	d.Show()
+}
+
+// showMergeGroupDialog moves every item of src into another group, re-parents src's sub-groups and then deletes src.
+func showMergeGroupDialog(src Group, removeGroup func()) {
+	others := []string{}
+	for _, g := range groups {
+		if g.ID != src.ID {
+			others = append(others, g.Name)
+		}
+	}
+	if len(others) == 0 {
+		dialog.ShowInformation("Merge Group", "There is no other group to merge into.", mainWindow)
+		return
+	}
+	var d dialog.Dialog
+	targetSelect := widget.NewSelect(others, nil)
+	targetSelect.SetSelected(others[0])
+	mergeBtn := widget.NewButton("Merge", func() {
+		i := slices.IndexFunc(groups, func(g Group) bool { return g.Name == targetSelect.Selected })
+		if i < 0 {
+			return
+		}
+		target := groups[i]
+		count := 0
+		for j := range items {
+			if slices.Contains(itemGroupIDs(&items[j]), src.ID) {
+				count++
+			}
+		}
+		msg := fmt.Sprintf("Move %d item(s) from '%s' into '%s' and delete '%s'?", count, src.Name, target.Name, src.Name)
+		dialog.ShowConfirm("Merge Groups", msg, func(ok bool) {
+			if !ok {
+				return
+			}
+			for j := range items {
+				ids := itemGroupIDs(&items[j])
+				if !slices.Contains(ids, src.ID) {
+					continue
+				}
+				if slices.Contains(ids, target.ID) {
+					setItemGroups(&items[j], slices.DeleteFunc(ids, func(id string) bool { return id == src.ID }))
+				} else {
+					replaceItemGroup(&items[j], src.ID, target.ID)
+				}
+			}
+			newParent := target.ID
+			if groupParent(target) != nil {
+				newParent = ""
+			}
+			for j := range groups {
+				if groups[j].ParentID == src.ID {
+					groups[j].ParentID = newParent
+				}
+			}
+			if appSettings.DefaultGroup == src.Name {
+				appSettings.DefaultGroup = target.Name
+				saveSettings()
+			}
+			saveData()
+			if sbGroupSelect.Selected == src.Name {
+				sbGroupSelect.SetSelected(target.Name)
+			}
+			d.Hide()
+			removeGroup()
+		}, mainWindow)
+	})
+	mergeBtn.Importance = widget.HighImportance
+	d = dialog.NewCustom("Merge '"+src.Name+"'", "Cancel", container.NewVBox(widget.NewLabel("Merge into:"), targetSelect, mergeBtn), mainWindow)
+	d.Show()
+}
+
+// showDeleteGroupWithItems asks what to do with the items of a group being deleted, so they aren't left orphaned.
+// count is the items only in this group; items that are also in other groups just lose this membership.
+func showDeleteGroupWithItems(grp Group, count int, removeGroup func()) {
+	var d dialog.Dialog
+	others := []string{}
+	for _, g := range groups {
+		if g.ID != grp.ID {
+			others = append(others, g.Name)
+		}
+	}
+	targetSelect := widget.NewSelect(others, nil)
+	moveBtn := widget.NewButton("Move Items and Delete Group", func() {
+		targetID := ""
+		for _, g := range groups {
+			if g.Name == targetSelect.Selected && g.ID != grp.ID {
+				targetID = g.ID
+			}
+		}
+		if targetID == "" {
+			return
+		}
+		for i := range items {
+			replaceItemGroup(&items[i], grp.ID, targetID)
+		}
+		saveData()
+		if sbGroupSelect.Selected == grp.Name {
+			sbGroupSelect.SetSelected(targetSelect.Selected)
+		}
+		d.Hide()
+		removeGroup()
+	})
+	moveBtn.Importance = widget.HighImportance
+	if len(others) > 0 {
+		targetSelect.SetSelected(others[0])
+	} else {
+		targetSelect.Disable()
+		moveBtn.Disable()
+	}
+	deleteLabel := fmt.Sprintf("Delete Group and %d Items", count)
+	if count == 0 {
+		deleteLabel = "Delete Group (items stay in their other groups)"
+	}
+	deleteBtn := widget.NewButton(deleteLabel, func() {
+		dialog.ShowConfirm("Delete Items", fmt.Sprintf("Permanently delete %d items that are only in '%s'? Items also in other groups are kept there.", count, grp.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			kept := []TodoItem{}
+			for _, it := range items {
+				ids := itemGroupIDs(&it)
+				switch {
+				case !slices.Contains(ids, grp.ID):
+					kept = append(kept, it)
+				case len(ids) > 1:
+					setItemGroups(&it, slices.DeleteFunc(ids, func(id string) bool { return id == grp.ID }))
+					kept = append(kept, it)
+				case it.ID == currentEditItemID:
+					resetSidebar()
+				}
+			}
+			items = kept
+			saveData()
+			d.Hide()
+			removeGroup()
+		}, mainWindow)
+	})
+	deleteBtn.Importance = widget.DangerImportance
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("'%s' still has %d items.", grp.Name, count)),
+		widget.NewLabel("Move them to:"), targetSelect, moveBtn,
+		widget.NewSeparator(), deleteBtn,
+	)
+	d = dialog.NewCustom("Delete Group", "Cancel", content, mainWindow)
+	d.Show()
+}
+
+// groupIDForName finds a group by name, creating it when an import references one this calendar doesn't have.
+func groupIDForName(name string) string {
+	for _, g := range groups {
+		if g.Name == name {
+			return g.ID
+		}
+	}
+	g := Group{ID: fmt.Sprintf("g-%d", time.Now().UnixNano()), Name: name, ColorHex: PresetColors[len(groups)%len(PresetColors)], SortOrder: nextGroupSortOrder()}
+	groups = append(groups, g)
+	saveGroups()
+	return g.ID
+}
+func parseICSTime(value string, params map[string][]string) (time.Time, error) {
+	loc := time.Local
+	if tz, ok := params[string(ical.ParameterTzid)]; ok && len(tz) > 0 {
+		name := strings.Trim(tz[0], "\"")
+		if iana, ok := windowsTimezones[name]; ok {
+			name = iana
+		}
+		if l, err := time.LoadLocation(name); err == nil {
+			loc = l
+		}
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.In(time.Local), err
+	}
+	if len(value) == 8 {
+		return time.ParseInLocation("20060102", value, time.Local)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t.In(time.Local), err
+}
+func exportICS() {
+	var d dialog.Dialog
+	y, m, _ := currentViewDate.Date()
+	first := time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+	btnFrom, getFrom, setFrom := createDatePickerButton(mainWindow, nil)
+	btnTo, getTo, setTo := createDatePickerButton(mainWindow, nil)
+	setFrom(first.Format("2006-01-02"))
+	setTo(first.AddDate(0, 1, -1).Format("2006-01-02"))
+	allCheck := widget.NewCheck("Export all items", func(b bool) {
+		if b {
+			btnFrom.Disable()
+			btnTo.Disable()
+		} else {
+			btnFrom.Enable()
+			btnTo.Enable()
+		}
+	})
+	btnNext := widget.NewButton("Export", func() {
+		selected := items
+		if !allCheck.Checked {
+			from, err1 := time.ParseInLocation("2006-01-02", getFrom(), time.Local)
+			to, err2 := time.ParseInLocation("2006-01-02", getTo(), time.Local)
+			if err1 != nil || err2 != nil || to.Before(from) {
+				dialog.ShowError(fmt.Errorf("invalid date range"), mainWindow)
+				return
+			}
+			to = to.AddDate(0, 0, 1)
+			selected = []TodoItem{}
+			for _, item := range items {
+				s, err := parseItemTime(item.Start)
+				if err == nil && !s.Before(from) && s.Before(to) {
+					selected = append(selected, item)
+				}
+			}
+		}
+		d.Hide()
+		cal := buildICSCalendar(selected)
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			_, _ = writer.Write([]byte(cal.Serialize()))
+			_ = writer.Close()
+			dialog.ShowInformation("Success", fmt.Sprintf("Exported %d items", len(selected)), mainWindow)
+		}, mainWindow)
+		saveDialog.SetFileName("my_calendar.ics")
+		saveDialog.Show()
+	})
+	btnNext.Importance = widget.HighImportance
+	content := container.NewVBox(widget.NewLabel("From"), btnFrom, widget.NewLabel("To"), btnTo, allCheck, btnNext)
+	d = dialog.NewCustom("Export .ICS", "Cancel", container.NewPadded(content), mainWindow)
+	d.Resize(fyne.NewSize(300, 300))
+	d.Show()
+}
+func buildICSCalendar(exportItems []TodoItem) *ical.Calendar {
+	cal := ical.NewCalendar()
+	cal.SetMethod(ical.MethodPublish)
+	gName := make(map[string]string)
+	for _, g := range groups {
+		gName[g.ID] = g.Name
+	}
+	addEvent := func(uid string, item TodoItem) *ical.VEvent {
+		evt := cal.AddEvent(uid)
+		s, _ := parseItemTime(item.Start)
+		e, _ := parseItemTime(item.End)
+		if item.AllDay {
+			evt.SetAllDayStartAt(s)
+			evt.SetAllDayEndAt(time.Date(e.Year(), e.Month(), e.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1))
+		} else {
+			evt.SetStartAt(s)
+			evt.SetEndAt(e)
+		}
+		evt.SetSummary(item.Title)
+		for _, id := range itemGroupIDs(&item) {
+			if name := gName[id]; name != "" {
+				evt.AddProperty(icsPropGroup, name)
+			}
+		}
+		if item.Completed {
+			evt.SetProperty(icsPropCompleted, "TRUE")
+		}
+		if item.Availability == AvailabilityFree {
+			evt.SetProperty(ical.ComponentPropertyTransp, "TRANSPARENT")
+		}
+		if item.Priority != "" {
+			evt.SetPriority(icsPriority(item.Priority))
+		}
+		for _, tag := range item.Tags {
+			evt.AddCategory(tag)
+		}
+		for _, link := range item.Attachments {
+			if u, err := attachmentURL(link); err == nil {
+				evt.AddAttachment(u.String())
+			}
+		}
+		return evt
+	}
+	series := make(map[string][]TodoItem)
+	seriesIDs := []string{}
+	for _, item := range exportItems {
+		if item.SeriesID == "" {
+			addEvent(item.ID, item)
+			continue
+		}
+		if _, seen := series[item.SeriesID]; !seen {
+			seriesIDs = append(seriesIDs, item.SeriesID)
+		}
+		series[item.SeriesID] = append(series[item.SeriesID], item)
+	}
+	for _, sid := range seriesIDs {
+		occ := series[sid]
+		sort.Slice(occ, func(a, b int) bool { return occ[a].Start < occ[b].Start })
+		starts := make([]time.Time, len(occ))
+		for i := range occ {
+			starts[i], _ = parseItemTime(occ[i].Start)
+		}
+		rule, exdates, ok := inferSeriesRule(starts)
+		if !ok {
+			for _, item := range occ {
+				addEvent(item.ID, item)
+			}
+			continue
+		}
+		first := occ[0]
+		evt := addEvent(sid, first)
+		evt.AddRrule(rule)
+		// Occurrences edited on their own go out as RECURRENCE-ID overrides of the series' VEVENT.
+		for _, it := range occ[1:] {
+			if !occurrenceDiffers(first, it) {
+				continue
+			}
+			s, _ := parseItemTime(it.Start)
+			override := addEvent(sid, it)
+			if it.AllDay {
+				override.SetProperty(ical.ComponentPropertyRecurrenceId, s.Format("20060102"), ical.WithValue(string(ical.ValueDataTypeDate)))
+			} else {
+				override.SetProperty(ical.ComponentPropertyRecurrenceId, s.UTC().Format("20060102T150405Z"))
+			}
+		}
+		for _, it := range occ {
+			for _, ex := range it.Exceptions {
+				if t, err := parseItemTime(ex); err == nil && !slices.ContainsFunc(exdates, t.Equal) {
+					exdates = append(exdates, t)
+				}
+			}
+		}
+		for _, x := range exdates {
+			if occ[0].AllDay {
+				evt.AddExdate(x.Format("20060102"), ical.WithValue(string(ical.ValueDataTypeDate)))
+			} else {
+				evt.AddExdate(x.UTC().Format("20060102T150405Z"))
+			}
+		}
+	}
+	return cal
+}
+
+// occurrenceDiffers reports whether an occurrence carries its own edits compared to the series' first one.
+func occurrenceDiffers(first, it TodoItem) bool {
+	s1, _ := parseItemTime(first.Start)
+	e1, _ := parseItemTime(first.End)
+	s2, _ := parseItemTime(it.Start)
+	e2, _ := parseItemTime(it.End)
+	return it.Title != first.Title || it.Completed != first.Completed || it.Priority != first.Priority ||
+		it.Availability != first.Availability || e2.Sub(s2) != e1.Sub(s1) || !slices.Equal(it.Tags, first.Tags) ||
+		!slices.Equal(itemGroupIDs(&it), itemGroupIDs(&first)) || !slices.Equal(it.Attachments, first.Attachments)
+}
+
+// inferSeriesRule tries the app's own recurrence modes, stepped by nextOccurrence exactly as the series was
+// generated, and keeps the one needing the fewest exdates. Gaps left by "This Only" deletes come back as exdates.
+func inferSeriesRule(starts []time.Time) (rule string, exdates []time.Time, ok bool) {
+	if len(starts) < 2 {
+		return "", nil, false
+	}
+	have := make(map[string]bool)
+	for _, t := range starts {
+		have[t.Format("2006-01-02 15:04")] = true
+	}
+	first, last := starts[0], starts[len(starts)-1]
+	type candidate struct {
+		rule  recurrenceRule
+		rrule string
+	}
+	candidates := []candidate{
+		{recurrenceRule{Mode: recModeWeekdays}, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{recurrenceRule{Mode: recModeWeekends}, "FREQ=WEEKLY;BYDAY=SA,SU"},
+	}
+	// Clamped month ends need BYMONTHDAY/BYSETPOS, otherwise other clients skip the short months.
+	monthDay := ""
+	switch d := first.Day(); {
+	case d == 31:
+		monthDay = ";BYMONTHDAY=-1"
+	case d > 28:
+		days := []string{}
+		for i := 28; i <= d; i++ {
+			days = append(days, strconv.Itoa(i))
+		}
+		monthDay = ";BYMONTHDAY=" + strings.Join(days, ",") + ";BYSETPOS=-1"
+	}
+	yearDay := ""
+	if first.Month() == time.February && first.Day() == 29 {
+		yearDay = ";BYMONTH=2;BYMONTHDAY=-1"
+	}
+	monthLen := first.AddDate(0, 1, -first.Day()).Day()
+	nths := []int{}
+	if nth := (first.Day()-1)/7 + 1; nth <= 4 {
+		nths = append(nths, nth)
+	}
+	if first.Day()+7 > monthLen {
+		nths = append(nths, -1)
+	}
+	for i := 1; i < len(starts); i++ {
+		a, b := starts[i-1], starts[i]
+		// On ties the earlier candidate wins, so the coarser unit is listed first.
+		if months := (b.Year()-a.Year())*12 + int(b.Month()-a.Month()); months > 0 {
+			if months%12 == 0 {
+				candidates = append(candidates, candidate{recurrenceRule{Mode: "Interval", Unit: "Year(s)", Interval: months / 12}, fmt.Sprintf("FREQ=YEARLY;INTERVAL=%d", months/12) + yearDay})
+			}
+			candidates = append(candidates, candidate{recurrenceRule{Mode: "Interval", Unit: "Month(s)", Interval: months}, fmt.Sprintf("FREQ=MONTHLY;INTERVAL=%d", months) + monthDay})
+			for _, nth := range nths {
+				r := recurrenceRule{Mode: "Monthly Weekday", Interval: months, Weekday: first.Weekday().String(), Nth: nth}
+				candidates = append(candidates, candidate{r, fmt.Sprintf("FREQ=MONTHLY;INTERVAL=%d;BYDAY=%d%s", months, nth, icsWeekdays[first.Weekday()])})
+			}
+		}
+		if days := daysBetween(a, b); days > 0 {
+			if days%7 == 0 {
+				candidates = append(candidates, candidate{recurrenceRule{Mode: "Interval", Unit: "Week(s)", Interval: days / 7}, fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", days/7)})
+			}
+			candidates = append(candidates, candidate{recurrenceRule{Mode: "Interval", Unit: "Day(s)", Interval: days}, fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", days)})
+		}
+	}
+	tried := make(map[string]bool)
+	for _, c := range candidates {
+		if tried[c.rrule] {
+			continue
+		}
+		tried[c.rrule] = true
+		matched, generated := 0, 0
+		var missing []time.Time
+		for t := first; !t.After(last); t = nextOccurrence(first, t, c.rule, generated-1) {
+			generated++
+			if have[t.Format("2006-01-02 15:04")] {
+				matched++
+			} else {
+				missing = append(missing, t)
+			}
+		}
+		if matched == len(have) && (!ok || len(missing) < len(exdates)) {
+			rule, exdates, ok = fmt.Sprintf("%s;COUNT=%d", c.rrule, generated), missing, true
+		}
+	}
+	return rule, exdates, ok
+}
+func exportCSV() {
+	gName := make(map[string]string)
+	for _, g := range groups {
+		gName[g.ID] = g.Name
+	}
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		w := csv.NewWriter(writer)
+		_ = w.Write([]string{"Title", "Group", "Other Groups", "Type", "Start", "End", "Completed", "SeriesID"})
+		for _, item := range items {
+			others := []string{}
+			for _, id := range itemGroupIDs(&item)[1:] {
+				others = append(others, gName[id])
+			}
+			_ = w.Write([]string{item.Title, gName[item.GroupID], strings.Join(others, "; "), string(item.Type), item.Start, item.End, strconv.FormatBool(item.Completed), item.SeriesID})
+		}
+		w.Flush()
+		_ = writer.Close()
+		if err := w.Error(); err != nil {
+			dialog.ShowError(err, mainWindow)
+			return
+		}
+		dialog.ShowInformation("Success", "Exported", mainWindow)
+	}, mainWindow)
+	saveDialog.SetFileName("my_calendar.csv")
+	saveDialog.Show()
+}
+
+var csvImportFields = []string{"Title", "Group", "Type", "Start", "End", "Completed"}
+
+func importCSV() {
+	if blockReadOnly() {
+		return
+	}
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		r := csv.NewReader(reader)
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil || len(records) < 2 {
+			dialog.ShowError(fmt.Errorf("no rows found in CSV"), mainWindow)
+			return
+		}
+		showCSVColumnMapping(records[0], records[1:])
+	}, mainWindow)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+	fd.Show()
+}
+
+// showCSVColumnMapping lets the user pick which column feeds each field; headers matching a field name are preselected.
+func showCSVColumnMapping(header []string, rows [][]string) {
+	const none = "(none)"
+	options := append([]string{none}, header...)
+	selects := make(map[string]*widget.Select)
+	form := widget.NewForm()
+	for _, field := range csvImportFields {
+		sel := widget.NewSelect(options, nil)
+		sel.SetSelected(none)
+		for _, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), field) {
+				sel.SetSelected(h)
+				break
+			}
+		}
+		selects[field] = sel
+		form.Append(field, sel)
+	}
+	dialog.ShowCustomConfirm("Map CSV Columns", "Import", "Cancel", container.NewVBox(widget.NewLabel(fmt.Sprintf("%d rows found", len(rows))), form), func(ok bool) {
+		if !ok {
+			return
+		}
+		cols := make(map[string]int)
+		for field, sel := range selects {
+			cols[field] = slices.Index(header, sel.Selected)
+		}
+		if cols["Title"] < 0 || cols["Start"] < 0 {
+			dialog.ShowError(fmt.Errorf("title and start columns are required"), mainWindow)
+			return
+		}
+		parsed, skipped := parseCSVRows(rows, cols)
+		if len(parsed) == 0 {
+			dialog.ShowError(fmt.Errorf("no importable rows (%d skipped)", skipped), mainWindow)
+			return
+		}
+		chooseImportMode(parsed, nil, skipped)
+	}, mainWindow)
+}
+
+// parseCSVRows turns spreadsheet rows into items. Start/End accept "2006-01-02 15:04" or a bare date (all-day).
+func parseCSVRows(rows [][]string, cols map[string]int) (parsed []TodoItem, skipped int) {
+	cell := func(row []string, field string) string {
+		if i := cols[field]; i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	parseCell := func(s string) (time.Time, bool, bool) {
+		if t, err := parseItemTime(s); err == nil {
+			return t, false, true
+		}
+		if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+			return t, true, true
+		}
+		return time.Time{}, false, false
+	}
+	stamp := time.Now().UnixNano()
+	for n, row := range rows {
+		title := cell(row, "Title")
+		start, allDay, ok := parseCell(cell(row, "Start"))
+		if title == "" || !ok {
+			skipped++
+			continue
+		}
+		end := start
+		if e, _, ok := parseCell(cell(row, "End")); ok && !e.Before(start) {
+			end = e
+		}
+		if allDay {
+			end = time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 0, 0, time.Local)
+		}
+		iType := TypeTask
+		if strings.EqualFold(cell(row, "Type"), string(TypeEvent)) || (cell(row, "Type") == "" && !end.Equal(start)) {
+			iType = TypeEvent
+		}
+		item := TodoItem{ID: fmt.Sprintf("csv-%d-%d", stamp, n), Title: title, Start: start.Format("2006-01-02 15:04"), End: end.Format("2006-01-02 15:04"), Type: iType, AllDay: allDay, GroupName: cell(row, "Group"), CreatedAt: time.Now().Format("2006-01-02 15:04")}
+		if item.GroupName == "" && len(groups) > 0 {
+			item.GroupID = groups[0].ID
+		} else if item.GroupName != "" {
+			for _, name := range strings.Split(cell(row, "Other Groups"), ";") {
+				if name = strings.TrimSpace(name); name != "" {
+					item.ImportGroups = append(item.ImportGroups, name)
+				}
+			}
+		}
+		if done, err := strconv.ParseBool(cell(row, "Completed")); err == nil {
+			setCompleted(&item, done)
+		}
+		parsed = append(parsed, item)
+	}
+	return parsed, skipped
+}
+
+// exportMonthPrint saves currentViewDate's month as a printable page; the file extension picks PNG, anything else is PDF.
+func exportMonthPrint() {
+	month := time.Date(currentViewDate.Year(), currentViewDate.Month(), 1, 0, 0, 0, 0, time.Local)
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		img := renderMonthImage(month)
+		if strings.EqualFold(writer.URI().Extension(), ".png") {
+			err = png.Encode(writer, img)
+		} else {
+			err = writePDFImage(writer, img, 792, 612)
+		}
+		if err != nil {
+			dialog.ShowError(err, mainWindow)
+			return
+		}
+		dialog.ShowInformation("Success", "Exported "+formatDate(month, "January 2006"), mainWindow)
+	}, mainWindow)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pdf", ".png"}))
+	saveDialog.SetFileName("calendar-" + month.Format("2006-01") + ".pdf")
+	saveDialog.Show()
+}
+
+// renderMonthImage lays the month out on a white landscape page (letter proportions) with fixed print colors,
+// independent of the app theme, and rasterizes it with fyne's software renderer at 2x.
+func renderMonthImage(month time.Time) image.Image {
+	const pageW, pageH, margin, lineH = float32(1100), float32(850), float32(24), float32(15)
+	ink := color.Black
+	faint := color.Gray{Y: 120}
+	page := container.NewWithoutLayout()
+	place := func(o fyne.CanvasObject, x, y, w, h float32) {
+		o.Move(fyne.NewPos(x, y))
+		o.Resize(fyne.NewSize(w, h))
+		page.Add(o)
+	}
+	fit := func(s string, size, width float32) string {
+		r := []rune(s)
+		for len(r) > 0 && fyne.MeasureText(string(r), size, fyne.TextStyle{}).Width > width {
+			r = r[:len(r)-1]
+			s = string(r) + "…"
+		}
+		return s
+	}
+	place(canvas.NewRectangle(color.White), 0, 0, pageW, pageH)
+	title := canvas.NewText(formatDate(month, "January 2006"), ink)
+	title.TextSize = 26
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	place(title, margin, 14, pageW-2*margin, 34)
+	cellW := (pageW - 2*margin) / 7
+	for i, name := range weekdayHeaders(false) {
+		t := canvas.NewText(name, faint)
+		t.TextSize = 13
+		t.TextStyle = fyne.TextStyle{Bold: true}
+		t.Alignment = fyne.TextAlignCenter
+		place(t, margin+float32(i)*cellW, 60, cellW, 20)
+	}
+	groupColors := make(map[string]color.Color)
+	for _, g := range groups {
+		groupColors[g.ID] = parseHexColor(g.ColorHex)
+	}
+	offset := (int(month.Weekday()) + 6) % 7
+	daysInMonth := month.AddDate(0, 1, -1).Day()
+	rows := (offset + daysInMonth + 6) / 7
+	top := float32(86)
+	cellH := (pageH - margin - top) / float32(rows)
+	maxLines := int((cellH - 28) / lineH)
+	for d := 1; d <= daysInMonth; d++ {
+		slot := offset + d - 1
+		x, y := margin+float32(slot%7)*cellW, top+float32(slot/7)*cellH
+		cell := canvas.NewRectangle(color.White)
+		cell.StrokeColor = color.Gray{Y: 190}
+		cell.StrokeWidth = 1
+		place(cell, x, y, cellW, cellH)
+		num := canvas.NewText(strconv.Itoa(d), ink)
+		num.TextSize = 14
+		num.TextStyle = fyne.TextStyle{Bold: true}
+		place(num, x+6, y+4, cellW-12, 18)
+		dayStart := time.Date(month.Year(), month.Month(), d, 0, 0, 0, 0, time.Local)
+		dayItems := itemsOnDay(dayStart, time.Date(month.Year(), month.Month(), d, 23, 59, 59, 0, time.Local))
+		shown := len(dayItems)
+		if shown > maxLines {
+			shown = max(0, maxLines-1)
+		}
+		for k, item := range dayItems[:shown] {
+			ly := y + 26 + float32(k)*lineH
+			text := item.Title
+			if !item.AllDay {
+				s, _ := parseItemTime(item.Start)
+				text = formatClock(s) + " " + text
+			}
+			textColor := color.Color(ink)
+			if item.Completed {
+				text = "✓ " + text
+				textColor = faint
+			}
+			c := itemColor(item, groupColors)
+			place(canvas.NewRectangle(c), x+6, ly+4, 6, 6)
+			t := canvas.NewText(fit(text, 11, cellW-24), textColor)
+			t.TextSize = 11
+			place(t, x+16, ly, cellW-22, lineH)
+		}
+		if hidden := len(dayItems) - shown; hidden > 0 {
+			more := canvas.NewText(fmt.Sprintf("+%d more", hidden), faint)
+			more.TextSize = 10
+			place(more, x+16, y+26+float32(shown)*lineH, cellW-22, lineH)
+		}
+	}
+	c := software.NewCanvas()
+	c.SetPadded(false)
+	c.SetScale(2)
+	c.SetContent(page)
+	c.Resize(fyne.NewSize(pageW, pageH))
+	return c.Capture()
+}
+
+// writePDFImage writes a one-page PDF showing img stretched over a widthPt x heightPt page, embedded as JPEG.
+func writePDFImage(w io.Writer, img image.Image, widthPt, heightPt int) error {
+	var jpg strings.Builder
+	if err := jpeg.Encode(&jpg, img, &jpeg.Options{Quality: 92}); err != nil {
+		return err
+	}
+	bounds := img.Bounds()
+	content := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im0 Do Q", widthPt, heightPt)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im0 5 0 R >> >> /Contents 4 0 R >>", widthPt, heightPt),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream", bounds.Dx(), bounds.Dy(), jpg.Len(), jpg.String()),
+	}
+	var out strings.Builder
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+type calendarBackup struct {
+	Version  int        `json:"version,omitempty"`
+	Calendar string     `json:"calendar"`
+	Exported string     `json:"exported"`
+	Groups   []Group    `json:"groups"`
+	Items    []TodoItem `json:"items"`
+}
+
+func exportBackup() {
+	if blockReadOnly() {
+		return
+	}
+	data, _ := json.MarshalIndent(calendarBackup{Version: dataVersion, Calendar: activeCalendarName, Exported: time.Now().Format(time.RFC3339), Groups: groups, Items: items}, "", " ")
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write(data); err != nil {
+			dialog.ShowError(err, mainWindow)
+			return
+		}
+		dialog.ShowInformation("Success", "Exported", mainWindow)
+	}, mainWindow)
+	saveDialog.SetFileName(strings.ReplaceAll(activeCalendarName, " ", "_") + "_backup.json")
+	saveDialog.Show()
+}
+func importBackup() {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, _ := io.ReadAll(reader)
+		var backup calendarBackup
+		if err := json.Unmarshal(data, &backup); err != nil || (backup.Groups == nil && backup.Items == nil) {
+			dialog.ShowError(fmt.Errorf("not a calendar backup file"), mainWindow)
+			return
+		}
+		migrateItems(backup.Items, backup.Version, backup.Groups)
+		backup.Version = dataVersion
+		chooseBackupTarget(backup)
+	}, mainWindow)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	fd.Show()
+}
+func chooseBackupTarget(backup calendarBackup) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(backup.Calendar)
+	if backup.Calendar == "" || backup.Calendar == allCalendarsName {
+		nameEntry.SetText("Imported")
+	}
+	info := widget.NewLabel(fmt.Sprintf("%d items and %d groups. An existing calendar with this name will be replaced.", len(backup.Items), len(backup.Groups)))
+	info.Wrapping = fyne.TextWrapWord
+	restore := func(name string) {
+		dataFile, groupFile := calendarFilenames(name)
+		groupData, _ := json.MarshalIndent(backup.Groups, "", " ")
+		itemData := marshalItems(backup.Items)
+		if err := writeFileAtomic(groupFile, groupData); err != nil {
+			dialog.ShowError(err, mainWindow)
+			return
+		}
+		backupDataFile(dataFile)
+		if err := writeFileAtomic(dataFile, itemData); err != nil {
+			dialog.ShowError(err, mainWindow)
+			return
+		}
+		if calendarIndex(name) < 0 {
+			availableCalendars = append(availableCalendars, CalendarMeta{Name: name})
+			saveCalendarList()
+		}
+		switchCalendar(name)
+	}
+	dialog.ShowCustomConfirm("Import Backup", "Import", "Cancel", container.NewVBox(info, widget.NewLabel("Into Calendar"), nameEntry), func(ok bool) {
+		name := strings.TrimSpace(nameEntry.Text)
+		if !ok || name == "" {
+			return
+		}
+		if name == allCalendarsName {
+			dialog.ShowError(fmt.Errorf("'%s' is reserved", allCalendarsName), mainWindow)
+			return
+		}
+		if calendarIndex(name) >= 0 {
+			dialog.ShowConfirm("Replace Calendar", "Replace everything in '"+name+"' with this backup?", func(ok bool) {
+				if ok {
+					restore(name)
+				}
+			}, mainWindow)
+			return
+		}
+		restore(name)
+	}, mainWindow)
+}
+func createDatePickerButton(parent fyne.Window, onChanged func(string)) (*widget.Button, func() string, func(string)) {
+	selectedDate := time.Now()
+	btn := widget.NewButton(selectedDate.Format("2006-01-02"), nil)
+	setDate := func(dateStr string) {
+		t, err := time.Parse("2006-01-02", dateStr)
+		if err == nil {
+			selectedDate = t
+			btn.SetText(dateStr)
+		}
+	}
+	btn.OnTapped = func() {
+		showDatePicker(parent, selectedDate, func(t time.Time) {
+			selectedDate = t
+			dateStr := selectedDate.Format("2006-01-02")
+			btn.SetText(dateStr)
+			if onChanged != nil {
+				onChanged(dateStr)
+			}
+		})
+	}
+	return btn, func() string { return btn.Text }, setDate
+}
+func showDatePicker(parent fyne.Window, initial time.Time, onPick func(time.Time)) {
+	navDate := initial
+	isUpdating := false
+	startYear, endYear := 1900, 2100
+	years := []string{}
+	for i := startYear; i <= endYear; i++ {
+		years = append(years, strconv.Itoa(i))
+	}
+	grid := container.NewGridWithColumns(7)
+	yearList := widget.NewList(func() int { return len(years) }, func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("2000", fyne.TextAlignCenter, fyne.TextStyle{})
+	}, func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(years[i]) })
+	monthNames := []string{}
+	for m := time.January; m <= time.December; m++ {
+		monthNames = append(monthNames, formatDate(time.Date(2000, m, 1, 0, 0, 0, 0, time.Local), "January"))
+	}
+	monthSelect := widget.NewSelect(monthNames, nil)
+	yearBtn := widget.NewButton("Year", nil)
+	mainContent := container.NewStack(grid, container.NewScroll(yearList))
+	var d dialog.Dialog
+	var refreshPopup func()
+	yearBtn.OnTapped = func() {
+		if grid.Visible() {
+			grid.Hide()
+			yearList.Show()
+			idx := navDate.Year() - startYear
+			yearList.ScrollTo(idx)
+			yearList.Select(idx)
+		} else {
+			yearList.Hide()
+			grid.Show()
+			refreshPopup()
+		}
+	}
+	yearList.OnSelected = func(id widget.ListItemID) {
+		navDate = time.Date(startYear+id, navDate.Month(), 1, 0, 0, 0, 0, time.Local)
+		yearList.Hide()
+		grid.Show()
+		refreshPopup()
+	}
+	refreshPopup = func() {
+		isUpdating = true
+		monthSelect.SetSelectedIndex(int(navDate.Month()) - 1)
+		yearBtn.SetText(strconv.Itoa(navDate.Year()))
+		grid.Objects = nil
+		y, m, _ := navDate.Date()
+		first := time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+		off := int(first.Weekday())
+		if off == 0 {
+			off = 7
+		}
+		off--
+		dim := first.AddDate(0, 1, -1).Day()
+		for _, day := range weekdayHeaders(true) {
+			grid.Add(widget.NewLabelWithStyle(day, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+		}
+		for i := 0; i < off; i++ {
+			grid.Add(layout.NewSpacer())
+		}
+		for i := 1; i <= dim; i++ {
+			dayNum := i
+			dayBtn := widget.NewButton(strconv.Itoa(i), func() {
+				onPick(time.Date(y, m, dayNum, 0, 0, 0, 0, time.Local))
+				if d != nil {
+					d.Hide()
+				}
+			})
+			if y == time.Now().Year() && m == time.Now().Month() && i == time.Now().Day() {
+				dayBtn.Importance = widget.HighImportance
+			}
+			grid.Add(dayBtn)
+		}
+		isUpdating = false
+		grid.Refresh()
+	}
+	btnPrev := widget.NewButton("<", func() { navDate = navDate.AddDate(0, -1, 0); refreshPopup() })
+	btnNext := widget.NewButton(">", func() { navDate = navDate.AddDate(0, 1, 0); refreshPopup() })
+	monthSelect.OnChanged = func(s string) {
+		if isUpdating {
+			return
+		}
+		for i, m := range monthSelect.Options {
+			if m == s {
+				navDate = navDate.AddDate(0, (i+1)-int(navDate.Month()), 0)
+				refreshPopup()
+				break
+			}
+		}
+	}
+	navBar := container.NewBorder(nil, nil, btnPrev, btnNext, container.NewGridWithColumns(2, monthSelect, yearBtn))
+	yearList.Hide()
+	refreshPopup()
+	d = dialog.NewCustom("Select Date", "Cancel", container.NewPadded(container.NewBorder(navBar, nil, nil, nil, mainContent)), parent)
+	d.Resize(fyne.NewSize(350, 400))
+	d.Show()
+}
+func createTimePicker(onChange func()) (*widget.Select, *widget.Select, *widget.Select, *fyne.Container, func(string, string, string)) {
+	hours12 := []string{}
+	for i := 1; i <= 12; i++ {
+		hours12 = append(hours12, fmt.Sprintf("%02d", i))
+	}
+	hours24 := []string{}
+	for i := 0; i < 24; i++ {
+		hours24 = append(hours24, fmt.Sprintf("%02d", i))
+	}
+	minuteOptions := func(keep string) []string {
+		step := appSettings.MinuteStep
+		if step < 1 || step > 30 {
+			step = 5
+		}
+		mins := []string{}
+		found := keep == ""
+		for i := 0; i < 60; i += step {
+			v := fmt.Sprintf("%02d", i)
+			mins = append(mins, v)
+			found = found || v == keep
+		}
+		if !found {
+			mins = append(mins, keep)
+			sort.Strings(mins)
+		}
+		return mins
+	}
+	applying := false
+	changed := func(s string) {
+		if onChange != nil && !applying {
+			onChange()
+		}
+	}
+	h := widget.NewSelect(hours12, changed)
+	m := widget.NewSelect(minuteOptions(""), changed)
+	ap := widget.NewSelect([]string{"AM", "PM"}, changed)
+	h.SetSelected("09")
+	m.SetSelected("00")
+	ap.SetSelected("AM")
+	grid := container.NewGridWithColumns(3, h, m, ap)
+	is24 := false
+	applyFormat := func() {
+		m.Options = minuteOptions(m.Selected)
+		m.Refresh()
+		if appSettings.Use24Hour == is24 {
+			return
+		}
+		applying = true
+		hour, _ := strconv.Atoi(h.Selected)
+		if is24 {
+			ampm := "AM"
+			if hour >= 12 {
+				ampm = "PM"
+			}
+			hour %= 12
+			if hour == 0 {
+				hour = 12
+			}
+			h.Options = hours12
+			h.SetSelected(fmt.Sprintf("%02d", hour))
+			ap.SetSelected(ampm)
+			ap.Show()
+			grid.Layout = layout.NewGridLayoutWithColumns(3)
+		} else {
+			if ap.Selected == "PM" && hour != 12 {
+				hour += 12
+			}
+			if ap.Selected == "AM" && hour == 12 {
+				hour = 0
+			}
+			h.Options = hours24
+			h.SetSelected(fmt.Sprintf("%02d", hour))
+			ap.ClearSelected()
+			ap.Hide()
+			grid.Layout = layout.NewGridLayoutWithColumns(2)
+		}
+		is24 = appSettings.Use24Hour
+		applying = false
+		grid.Refresh()
+	}
+	applyFormat()
+	timePickerFormatters = append(timePickerFormatters, applyFormat)
+	setTime := func(hh, mm, ampm string) {
+		h.SetSelected(hh)
+		if !slices.Contains(m.Options, mm) {
+			m.Options = minuteOptions(mm)
+		}
+		m.SetSelected(mm)
+		if is24 {
+			ap.ClearSelected()
+		} else {
+			ap.SetSelected(ampm)
+		}
+	}
+	return h, m, ap, grid, setTime
+}
+func formatTimeParts(t time.Time) (string, string, string) {
+	if appSettings.Use24Hour {
+		return fmt.Sprintf("%02d", t.Hour()), fmt.Sprintf("%02d", t.Minute()), ""
+	}
+	h := t.Hour()
+	ap := "AM"
+	if h >= 12 {
+		ap = "PM"
+		if h > 12 {
+			h -= 12
+		}
+	}
+	if h == 0 {
+		h = 12
+	}
+	return fmt.Sprintf("%02d", h), fmt.Sprintf("%02d", t.Minute()), ap
+}
+
+// --- CALDAV SYNC ---
+
+// calDAVAccount points one local calendar at a remote CalDAV collection. The password is kept in app_settings.json as-is.
+type calDAVAccount struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type davMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			CalendarData string `xml:"prop>calendar-data"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+const calDAVQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><c:calendar-data/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>
+</c:calendar-query>`
+
+func showCalDAVSettings() {
+	if blockReadOnly() {
+		return
+	}
+	acct := appSettings.CalDAV[activeCalendarName]
+	urlEntry := widget.NewEntry()
+	urlEntry.PlaceHolder = "https://cloud.example.com/remote.php/dav/calendars/me/personal/"
+	urlEntry.SetText(acct.URL)
+	userEntry := widget.NewEntry()
+	userEntry.SetText(acct.Username)
+	passEntry := widget.NewPasswordEntry()
+	passEntry.SetText(acct.Password)
+	info := widget.NewLabel("Local items are pushed to the server, then events that only exist remotely are pulled in. Items are matched by UID; deletions are not synced.")
+	info.Wrapping = fyne.TextWrapWord
+	passWarning := widget.NewLabelWithStyle("Saved unencrypted in app_settings.json. Use an app-specific password if your server offers one.", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	passWarning.Wrapping = fyne.TextWrapWord
+	form := []*widget.FormItem{widget.NewFormItem("Server URL", urlEntry), widget.NewFormItem("Username", userEntry), widget.NewFormItem("Password", passEntry), widget.NewFormItem("", passWarning), widget.NewFormItem("", info)}
+	d := dialog.NewForm("CalDAV Sync: "+activeCalendarName, "Save & Sync", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		if appSettings.CalDAV == nil {
+			appSettings.CalDAV = make(map[string]calDAVAccount)
+		}
+		acct := calDAVAccount{URL: strings.TrimSpace(urlEntry.Text), Username: userEntry.Text, Password: passEntry.Text}
+		if acct.URL == "" {
+			delete(appSettings.CalDAV, activeCalendarName)
+			saveSettings()
+			return
+		}
+		appSettings.CalDAV[activeCalendarName] = acct
+		saveSettings()
+		syncCalDAV()
+	}, mainWindow)
+	d.Resize(fyne.NewSize(520, 420))
+	d.Show()
+}
+
+// syncCalDAV pushes every local item (or whole series) as its own resource, then adds remote events whose UID isn't known locally.
+// Network work runs off the UI goroutine; items are only touched back inside fyne.Do.
+func syncCalDAV() {
+	if blockReadOnly() {
+		return
+	}
+	acct, ok := appSettings.CalDAV[activeCalendarName]
+	if !ok || acct.URL == "" {
+		showCalDAVSettings()
+		return
+	}
+	flushPendingSave()
+	base := strings.TrimSuffix(acct.URL, "/") + "/"
+	payloads := calDAVPayloads(buildICSCalendar(items))
+	calendar := activeCalendarName
+	progress := dialog.NewCustomWithoutButtons("Syncing with CalDAV...", widget.NewProgressBarInfinite(), mainWindow)
+	progress.Show()
+	go func() {
+		client := &http.Client{Timeout: 30 * time.Second}
+		send := func(method, target, contentType, body string, headers map[string]string) (*http.Response, error) {
+			req, err := http.NewRequest(method, target, strings.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			req.SetBasicAuth(acct.Username, acct.Password)
+			req.Header.Set("Content-Type", contentType)
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			return client.Do(req)
+		}
+		pushed, failed := 0, 0
+		var firstErr error
+		for uid, body := range payloads {
+			resp, err := send(http.MethodPut, base+url.PathEscape(uid)+".ics", "text/calendar; charset=utf-8", body, nil)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode >= 300 {
+					err = fmt.Errorf("PUT %s: %s", uid, resp.Status)
+				}
+			}
+			if err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			pushed++
+		}
+		var remote []string
+		resp, err := send("REPORT", base, "application/xml; charset=utf-8", calDAVQuery, map[string]string{"Depth": "1"})
+		if err == nil {
+			var ms davMultistatus
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("REPORT: %s", resp.Status)
+			} else if err = xml.NewDecoder(resp.Body).Decode(&ms); err == nil {
+				for _, r := range ms.Responses {
+					for _, ps := range r.Propstat {
+						if strings.TrimSpace(ps.CalendarData) != "" {
+							remote = append(remote, ps.CalendarData)
+						}
+					}
+				}
+			}
+			resp.Body.Close()
+		}
+		fyne.Do(func() {
+			progress.Hide()
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("pushed %d items, but fetching from the server failed: %v", pushed, err), mainWindow)
+				return
+			}
+			if activeCalendarName != calendar {
+				return
+			}
+			pulled := mergeCalDAVEvents(remote, payloads)
+			msg := fmt.Sprintf("Pushed %d items, pulled %d new items.", pushed, pulled)
+			if failed > 0 {
+				msg += fmt.Sprintf("\n%d items failed to upload: %v", failed, firstErr)
+			}
+			dialog.ShowInformation("CalDAV Sync", msg, mainWindow)
+		})
+	}()
+}
+
+// calDAVPayloads serializes one VCALENDAR per UID, so a series and its RECURRENCE-ID overrides go up as one resource.
+func calDAVPayloads(cal *ical.Calendar) map[string]string {
+	byUID := make(map[string]*ical.Calendar)
+	for _, evt := range cal.Events() {
+		single, ok := byUID[evt.Id()]
+		if !ok {
+			single = ical.NewCalendar()
+			single.SetMethod(ical.MethodPublish)
+			byUID[evt.Id()] = single
+		}
+		single.Components = append(single.Components, evt)
+	}
+	payloads := make(map[string]string, len(byUID))
+	for uid, single := range byUID {
+		payloads[uid] = single.Serialize()
+	}
+	return payloads
+}
+
+// mergeCalDAVEvents adds remote events not already known by UID. Pulled items take the remote UID as their ID
+// (or SeriesID for recurring events), so the next push updates the same resource instead of duplicating it.
+func mergeCalDAVEvents(remote []string, known map[string]string) int {
+	added := 0
+	for _, data := range remote {
+		parsed, uids, _, err := parseICSItems([]byte(data))
+		if err != nil {
+			continue
+		}
+		for _, p := range parsed {
+			uid := uids[p.ID]
+			if _, ok := known[uid]; ok || uid == "" || isSkippedOccurrence(p, uid) {
+				continue
+			}
+			if p.SeriesID != "" {
+				p.SeriesID = uid
+			} else {
+				p.ID = uid
+			}
+			resolveImportGroups(&p)
+			items = append(items, p)
+			added++
+		}
+	}
+	if added > 0 {
+		saveData()
+		updateGroupDropdown()
+		refreshCalendar()
+		refreshKanban()
+	}
+	return added
+}
+
+// --- LOCALE ---
+
+// dateNames holds a language's month and weekday names; weekdays are Sunday-first to match time.Weekday.
+type dateNames struct {
+	months, shortMonths [12]string
+	days, shortDays     [7]string
+}
+
+var localeNames = map[string]dateNames{
+	"Deutsch": {
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	},
+	"Español": {
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"Français": {
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"Italiano": {
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"Nederlands": {
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+	"Português": {
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	},
+}
+
+// languageOptions lists the selectable languages, English (the Go default) first.
+func languageOptions() []string {
+	langs := []string{"English"}
+	for name := range localeNames {
+		langs = append(langs, name)
+	}
+	sort.Strings(langs[1:])
+	return langs
+}
+
+// formatDate is time.Format with the month and weekday names swapped for the configured language.
+func formatDate(t time.Time, layout string) string {
+	s := t.Format(layout)
+	names, ok := localeNames[appSettings.Language]
+	if !ok {
+		return s
+	}
+	month, day := t.Month().String(), t.Weekday().String()
+	return strings.NewReplacer(
+		month, names.months[t.Month()-1], day, names.days[t.Weekday()],
+		month[:3], names.shortMonths[t.Month()-1], day[:3], names.shortDays[t.Weekday()],
+	).Replace(s)
+}
+
+// weekdayHeaders returns Monday-first column labels; tiny gives the two-letter English form used by the mini calendar.
+func weekdayHeaders(tiny bool) []string {
+	headers := make([]string, 7)
+	names, ok := localeNames[appSettings.Language]
+	for i := range headers {
+		wd := time.Weekday((i + 1) % 7)
+		switch {
+		case ok:
+			headers[i] = strings.TrimSuffix(names.shortDays[wd], ".")
+		case tiny:
+			headers[i] = wd.String()[:2]
+		default:
+			headers[i] = wd.String()[:3]
+		}
+	}
+	return headers
+}
+func formatClock(t time.Time) string {
+	if appSettings.Use24Hour {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04 PM")
+}
+func updateGroupDropdown() {
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	options := []string{}
+	if appSettings.InboxEnabled {
+		options = append(options, inboxGroupName)
+	}
+	for _, g := range groups {
+		options = append(options, g.Name)
+	}
+	options = append(options, "+ Create New Group")
+	sbGroupSelect.Options = options
+	sbGroupSelect.Refresh()
+	refreshGroupFilter()
+}
+
+// groupParent returns the group's parent, or nil for top-level groups and groups whose parent no longer exists.
+func groupParent(g Group) *Group {
+	if g.ParentID == "" || g.ParentID == g.ID {
+		return nil
+	}
+	for i := range groups {
+		if groups[i].ID == g.ParentID {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+func hasSubGroups(groupID string) bool {
+	return slices.ContainsFunc(groups, func(g Group) bool { return g.ParentID == groupID && g.ID != groupID })
+}
+
+// columnOrder returns indexes into groups in kanban column order, each sub-group following its parent;
+// siblings without a SortOrder fall back to name order.
+func columnOrder() []int {
+	sorted := make([]int, len(groups))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(a, b int) bool {
+		ga, gb := groups[sorted[a]], groups[sorted[b]]
+		if ga.SortOrder != gb.SortOrder {
+			return ga.SortOrder < gb.SortOrder
+		}
+		return ga.Name < gb.Name
+	})
+	order := make([]int, 0, len(groups))
+	for _, i := range sorted {
+		if groupParent(groups[i]) != nil {
+			continue
+		}
+		order = append(order, i)
+		for _, j := range sorted {
+			if p := groupParent(groups[j]); p != nil && p.ID == groups[i].ID {
+				order = append(order, j)
+			}
+		}
+	}
+	return order
+}
+
+// nextGroupSortOrder places a new group after every existing column, including ones the user has reordered.
+func nextGroupSortOrder() int {
+	next := 1
+	for _, g := range groups {
+		next = max(next, g.SortOrder+1)
+	}
+	return next
+}
+
+// moveGroupColumn swaps a group with its neighbouring sibling, so sub-groups stay under their parent.
+func moveGroupColumn(groupID string, dir int) {
+	if blockReadOnly() {
+		return
+	}
+	pos := slices.IndexFunc(groups, func(g Group) bool { return g.ID == groupID })
+	if pos < 0 {
+		return
+	}
+	parentOf := func(g Group) string {
+		if p := groupParent(g); p != nil {
+			return p.ID
+		}
+		return ""
+	}
+	parent := parentOf(groups[pos])
+	siblings := []int{}
+	for _, i := range columnOrder() {
+		if parentOf(groups[i]) == parent {
+			siblings = append(siblings, i)
+		}
+	}
+	at := slices.Index(siblings, pos)
+	if at+dir < 0 || at+dir >= len(siblings) {
+		return
+	}
+	siblings[at], siblings[at+dir] = siblings[at+dir], siblings[at]
+	for rank, i := range siblings {
+		groups[i].SortOrder = rank + 1
+	}
+	saveGroups()
+	refreshKanban()
+}
+func isGroupHidden(groupID string) bool {
+	return slices.Contains(appSettings.HiddenGroups[activeCalendarName], groupID)
+}
+func setGroupHidden(groupID string, hidden bool) {
+	setGroupFlag(&appSettings.HiddenGroups, groupID, hidden)
+}
+func isGroupCollapsed(groupID string) bool {
+	return slices.Contains(appSettings.CollapsedGroups[activeCalendarName], groupID)
+}
+func setGroupCollapsed(groupID string, collapsed bool) {
+	setGroupFlag(&appSettings.CollapsedGroups, groupID, collapsed)
+}
+
+// setGroupFlag adds or removes groupID in the active calendar's entry of a per-calendar group set and persists it.
+func setGroupFlag(sets *map[string][]string, groupID string, on bool) {
+	if *sets == nil {
+		*sets = make(map[string][]string)
+	}
+	ids := slices.DeleteFunc((*sets)[activeCalendarName], func(id string) bool { return id == groupID })
+	if on {
+		ids = append(ids, groupID)
+	}
+	if len(ids) == 0 {
+		delete(*sets, activeCalendarName)
+	} else {
+		(*sets)[activeCalendarName] = ids
+	}
+	saveSettings()
+}
+func refreshGroupFilter() {
+	if groupFilterBar == nil {
+		return
+	}
+	groupFilterBar.Objects = nil
+	for _, g := range groups {
+		groupID := g.ID
+		swatch := canvas.NewRectangle(parseHexColor(g.ColorHex))
+		swatch.SetMinSize(fyne.NewSize(12, 12))
+		check := widget.NewCheck(g.Name, func(b bool) {
+			setGroupHidden(groupID, !b)
+			refreshCalendar()
+			refreshKanban()
+		})
+		check.Checked = !isGroupHidden(groupID)
+		groupFilterBar.Add(container.NewHBox(container.NewCenter(swatch), check))
+	}
+	groupFilterBar.Refresh()
+}
+
+// groupsLoaded is false when the active calendar's groups file (and its .bak) couldn't be read. Orphan recovery is
+// skipped then, as every item would look orphaned and lose its real groups.
+var groupsLoaded bool
+
+func loadGroups() bool {
+	_, groupFile := getFilenames()
+	err := readJSONFile(groupFile, &groups)
+	groupsLoaded = err == nil || os.IsNotExist(err)
+	if !groupsLoaded {
+		dialog.ShowError(err, mainWindow)
+	}
+	if len(groups) == 0 && os.IsNotExist(err) {
+		groups = []Group{{ID: "g-1", Name: "Work", ColorHex: "#3498DB"}, {ID: "g-2", Name: "Personal", ColorHex: "#2ECC71"}}
+		saveGroups()
+	}
+	return groupsLoaded
+}
+func saveGroups() {
+	if activeCalendarName == allCalendarsName {
+		return
+	}
+	_, groupFile := getFilenames()
+	file, _ := json.MarshalIndent(groups, "", " ")
+	_ = writeFileAtomic(groupFile, file)
+}
+
+// scheduleSave debounces sidebar edits so typing doesn't rewrite the data file on every keystroke.
+func scheduleSave() {
+	if saveTimer != nil {
+		saveTimer.Stop()
+	}
+	saveTimer = time.AfterFunc(500*time.Millisecond, func() {
+		fyne.Do(func() {
+			saveData()
+			sbSavedLabel.Show()
+			if savedHideTimer != nil {
+				savedHideTimer.Stop()
+			}
+			savedHideTimer = time.AfterFunc(2*time.Second, func() { fyne.Do(sbSavedLabel.Hide) })
+		})
+	})
+}
+func flushPendingSave() {
+	if saveTimer != nil && saveTimer.Stop() {
+		saveData()
+	}
+}
+func saveData() {
+	if saveTimer != nil {
+		saveTimer.Stop()
+	}
+	if activeCalendarName == allCalendarsName {
+		return
+	}
+	dataFile, _ := getFilenames()
+	backupDataFile(dataFile)
+	_ = writeFileAtomic(dataFile, marshalItems(items))
+}
+func backupPrefix(dataFile string) string {
+	return strings.TrimSuffix(filepath.Base(dataFile), ".json") + "_"
+}
+func backupDataFile(dataFile string) {
+	if appSettings.BackupCount <= 0 || time.Since(lastBackupAt[dataFile]) < 5*time.Minute {
+		return
+	}
+	current, err := os.ReadFile(dataFile)
+	if err != nil || !json.Valid(current) {
+		return
+	}
+	backupDir := filepath.Join(filepath.Dir(dataFile), "backups")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return
+	}
+	name := backupPrefix(dataFile) + time.Now().Format("20060102-150405") + ".json"
+	if err := os.WriteFile(filepath.Join(backupDir, name), current, 0644); err != nil {
+		return
+	}
+	lastBackupAt[dataFile] = time.Now()
+	snapshots := listBackups(dataFile)
+	for i := appSettings.BackupCount; i < len(snapshots); i++ {
+		_ = os.Remove(filepath.Join(backupDir, snapshots[i]))
+	}
+}
+func listBackups(dataFile string) []string {
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(dataFile), "backups"))
+	if err != nil {
+		return nil
+	}
+	prefix := backupPrefix(dataFile)
+	names := []string{}
+	for _, e := range entries {
+		rest, ok := strings.CutPrefix(e.Name(), prefix)
+		if ok && len(rest) == len("20060102-150405.json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names
+}
+func showBackupRestore() {
+	var d dialog.Dialog
+	dataFile, _ := getFilenames()
+	backupDir := filepath.Join(filepath.Dir(dataFile), "backups")
+	snapshots := listBackups(dataFile)
+	if len(snapshots) == 0 {
+		dialog.ShowInformation("Restore from Backup", "No backups for '"+activeCalendarName+"' yet.", mainWindow)
+		return
+	}
+	prefix := backupPrefix(dataFile)
+	list := widget.NewList(
+		func() int { return len(snapshots) },
+		func() fyne.CanvasObject { return widget.NewLabel("Snapshot") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			label := snapshots[i]
+			if t, err := time.ParseInLocation("20060102-150405", strings.TrimSuffix(strings.TrimPrefix(label, prefix), ".json"), time.Local); err == nil {
+				label = formatDate(t, "Mon, Jan 02 2006 15:04:05")
+			}
+			o.(*widget.Label).SetText(label)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		name := snapshots[id]
+		list.UnselectAll()
+		dialog.ShowConfirm("Restore", "Replace the current items of '"+activeCalendarName+"' with this backup?", func(ok bool) {
+			if !ok {
+				return
+			}
+			restored, _, err := readItemsFile(filepath.Join(backupDir, name), groups)
+			if err != nil {
+				dialog.ShowError(err, mainWindow)
+				return
+			}
+			lastBackupAt[dataFile] = time.Time{}
+			items = restored
+			saveData()
+			refreshCalendar()
+			refreshKanban()
+			resetSidebar()
+			d.Hide()
+		}, mainWindow)
 	}
-	kanbanContainer.Refresh()
+	d = dialog.NewCustom("Restore from Backup", "Close", list, mainWindow)
+	d.Resize(fyne.NewSize(400, 400))
+	d.Show()
 }
-func showMoveDialog(item *TodoItem) {
-	var d dialog.Dialog
-	opts := []string{}
-	for _, g := range groups {
-		if g.ID != item.GroupID {
-			opts = append(opts, g.Name)
-		}
+func loadData() {
+	dataFile, _ := getFilenames()
+	loaded, version, err := readItemsFile(dataFile, groups)
+	if err != nil && !os.IsNotExist(err) {
+		dialog.ShowError(err, mainWindow)
 	}
-	sel := widget.NewSelect(opts, nil)
-	sel.PlaceHolder = "Select Group"
-	btnConfirm := widget.NewButton("Move", func() {
-		if sel.Selected == "" {
-			return
-		}
-		for _, g := range groups {
-			if g.Name == sel.Selected {
-				item.GroupID = g.ID
-				break
-			}
+	if err == nil {
+		items = loaded
+		if version < dataVersion {
+			saveData()
 		}
-		saveData()
-		refreshCalendar()
-		refreshKanban()
-		d.Hide()
-	})
-	d = dialog.NewCustom("Move Item", "Cancel", container.NewPadded(container.NewVBox(widget.NewLabel(fmt.Sprintf("Move '%s' to:", item.Title)), sel, btnConfirm)), mainWindow)
-	d.Show()
+		recoverOrphanedItems()
+	}
+	seedFiredReminders()
+	validateItemDates()
 }
 
-// --- IMPORT / EXPORT / HELPERS ---
+// --- FILE WATCH ---
 
-func showSettingsDialog() {
-	var d dialog.Dialog
+var fileWatcher *fsnotify.Watcher
 
-	themeSelect := widget.NewSelect([]string{"Light", "Dark"}, func(s string) {
-		currentTheme = s
-		if s == "Light" {
-			myApp.Settings().SetTheme(theme.LightTheme())
-		} else {
-			myApp.Settings().SetTheme(theme.DarkTheme())
-		}
-	})
-	themeSelect.SetSelected(currentTheme)
+// ownWrites holds what this process last wrote to each path, so the watcher can tell our saves from outside edits.
+var ownWrites = make(map[string][]byte)
 
-	calSelect := widget.NewSelect(availableCalendars, func(s string) {
-		if s != activeCalendarName && s != "" {
-			switchCalendar(s)
-			d.Hide()
+// startFileWatch (re)watches the active calendar's data directory when enabled. The merged all-calendars view isn't watched.
+func startFileWatch() {
+	if fileWatcher != nil {
+		_ = fileWatcher.Close()
+		fileWatcher = nil
+	}
+	if !appSettings.WatchFiles || activeCalendarName == allCalendarsName {
+		return
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return
+	}
+	dataFile, groupFile := getFilenames()
+	// Watch the directory rather than the files: atomic saves replace them, which drops a per-file watch.
+	if err := w.Add(filepath.Dir(dataFile)); err != nil {
+		_ = w.Close()
+		return
+	}
+	fileWatcher = w
+	go func() {
+		var debounce *time.Timer
+		for {
+			select {
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				name := filepath.Clean(e.Name)
+				if name != filepath.Clean(dataFile) && name != filepath.Clean(groupFile) || !e.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(300*time.Millisecond, func() {
+					fyne.Do(func() {
+						if fileWatcher == w {
+							reloadChangedFiles(dataFile, groupFile)
+						}
+					})
+				})
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
 		}
-	})
-	calSelect.SetSelected(activeCalendarName)
-
-	manageCalBtn := widget.NewButton("Create / Delete Calendars", func() { d.Hide(); showCalendarManager() })
-	btnImport := widget.NewButtonWithIcon("Import .ICS", theme.FolderOpenIcon(), func() { importICS(); d.Hide() })
-	btnExport := widget.NewButtonWithIcon("Export .ICS", theme.DocumentSaveIcon(), func() { exportICS() })
-
-	content := container.NewVBox(
-		widget.NewLabelWithStyle("App Settings", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-		widget.NewSeparator(),
-		widget.NewLabel("Theme"), themeSelect,
-		widget.NewSeparator(),
-		widget.NewLabel("Active Calendar"), calSelect, manageCalBtn,
-		widget.NewSeparator(),
-		widget.NewLabel("Data Transfer"), container.NewGridWithColumns(2, btnImport, btnExport),
-	)
-	d = dialog.NewCustom("Settings", "Close", container.NewPadded(content), mainWindow)
-	d.Resize(fyne.NewSize(400, 500))
-	d.Show()
+	}()
 }
 
-func getFilenames() (string, string) {
-	prefix := strings.ReplaceAll(activeCalendarName, " ", "_")
-	return prefix + "_data.json", prefix + "_groups.json"
-}
-func loadCalendarList() {
-	file, err := os.ReadFile("calendars_meta.json")
-	if err == nil {
-		json.Unmarshal(file, &availableCalendars)
+// reloadChangedFiles reloads whichever of the active calendar's files differ from what we last wrote. A pending
+// sidebar save is flushed first when only the groups changed; if the items file changed too, the user picks a side.
+func reloadChangedFiles(dataFile, groupFile string) {
+	changed := map[string]bool{}
+	for _, path := range []string{dataFile, groupFile} {
+		if data, err := os.ReadFile(path); err == nil && json.Valid(data) && !bytes.Equal(data, ownWrites[path]) {
+			ownWrites[path] = data
+			changed[path] = true
+		}
 	}
-	if len(availableCalendars) == 0 {
-		availableCalendars = []string{"Default"}
-		saveCalendarList()
+	if len(changed) == 0 {
+		return
 	}
+	if !changed[dataFile] {
+		flushPendingSave()
+		groups = []Group{}
+		loadGroups()
+		reloadViews()
+		return
+	}
+	if saveTimer != nil && saveTimer.Stop() {
+		dialog.ShowConfirm("Calendar Changed on Disk", "'"+activeCalendarName+"' was changed by another program while you had unsaved edits.\nReload it and discard your edits? Cancel keeps your version and overwrites the file.", func(ok bool) {
+			if ok {
+				reloadCalendarFiles()
+			} else {
+				saveData()
+			}
+		}, mainWindow)
+		return
+	}
+	reloadCalendarFiles()
 }
-func saveCalendarList() {
-	file, _ := json.MarshalIndent(availableCalendars, "", " ")
-	_ = os.WriteFile("calendars_meta.json", file, 0644)
-}
-func switchCalendar(name string) {
-	activeCalendarName = name
+func reloadCalendarFiles() {
 	items = []TodoItem{}
 	groups = []Group{}
 	loadGroups()
 	loadData()
+	reloadViews()
+}
+func reloadViews() {
 	refreshCalendar()
 	refreshKanban()
 	updateGroupDropdown()
-	if len(groups) > 0 {
-		sbGroupSelect.SetSelected(groups[0].Name)
+	if i := slices.IndexFunc(items, func(it TodoItem) bool { return it.ID == currentEditItemID }); i >= 0 {
+		loadSidebarItem(&items[i])
+	} else if currentEditItemID != "" {
+		resetSidebar()
 	}
-	resetSidebar()
 }
-func showCalendarManager() {
-	var d dialog.Dialog
-	input := widget.NewEntry()
-	input.PlaceHolder = "New Calendar Name"
-	list := widget.NewList(
-		func() int { return len(availableCalendars) },
-		func() fyne.CanvasObject {
-			return container.NewHBox(widget.NewLabel("Name"), layout.NewSpacer(), widget.NewButtonWithIcon("", theme.DeleteIcon(), nil))
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			box := o.(*fyne.Container)
-			lbl := box.Objects[0].(*widget.Label)
-			btn := box.Objects[2].(*widget.Button)
-			name := availableCalendars[i]
-			lbl.SetText(name)
-			btn.OnTapped = func() {
-				if len(availableCalendars) <= 1 {
-					dialog.ShowError(fmt.Errorf("cannot delete last"), mainWindow)
-					return
-				}
-				dialog.ShowConfirm("Delete", "Delete '"+name+"'?", func(ok bool) {
-					if ok {
-						newList := []string{}
-						for _, c := range availableCalendars {
-							if c != name {
-								newList = append(newList, c)
-							}
-						}
-						availableCalendars = newList
-						saveCalendarList()
-						if activeCalendarName == name {
-							switchCalendar(availableCalendars[0])
-						}
-						d.Hide()
-						showCalendarManager()
-					}
-				}, mainWindow)
+
+// --- DATA VERSIONING ---
+
+// dataVersion is the item layout this build writes. Bump it together with a new entry in itemMigrations.
+const dataVersion = 2
+
+// itemsFile is how a calendar's items are stored. Files from before versioning are a bare array and count as version 0.
+type itemsFile struct {
+	Version int        `json:"version"`
+	Items   []TodoItem `json:"items"`
+}
+
+func (f *itemsFile) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		f.Version = 0
+		return json.Unmarshal(trimmed, &f.Items)
+	}
+	type plain itemsFile
+	return json.Unmarshal(b, (*plain)(f))
+}
+
+// itemMigrations[v] upgrades items from version v to v+1, given the calendar's groups.
+var itemMigrations = []func(items []TodoItem, groups []Group){
+	// 0 → 1: items named their group rather than referencing its ID.
+	func(items []TodoItem, groups []Group) {
+		for i := range items {
+			if items[i].GroupID != "" || items[i].GroupName == "" {
+				continue
+			}
+			if j := slices.IndexFunc(groups, func(g Group) bool { return g.Name == items[i].GroupName }); j >= 0 {
+				items[i].GroupID = groups[j].ID
+				items[i].GroupName = ""
 			}
-		},
-	)
-	createBtn := widget.NewButton("Create", func() {
-		if input.Text == "" {
-			return
 		}
-		for _, c := range availableCalendars {
-			if c == input.Text {
-				return
+	},
+	// 1 → 2: items can belong to several groups; GroupID stays the primary one.
+	func(items []TodoItem, _ []Group) {
+		for i := range items {
+			if items[i].GroupID == "" && len(items[i].GroupIDs) > 0 {
+				items[i].GroupID = items[i].GroupIDs[0]
 			}
+			setItemGroups(&items[i], itemGroupIDs(&items[i]))
 		}
-		availableCalendars = append(availableCalendars, input.Text)
-		saveCalendarList()
-		switchCalendar(input.Text)
-		d.Hide()
-	})
-	d = dialog.NewCustom("Manage Calendars", "Close", container.NewPadded(container.NewBorder(container.NewVBox(widget.NewLabel("Create New:"), container.NewBorder(nil, nil, nil, createBtn, input), widget.NewSeparator()), nil, nil, nil, list)), mainWindow)
-	d.Resize(fyne.NewSize(400, 500))
-	d.Show()
+	},
 }
-func showGroupManager() {
-	var d dialog.Dialog
-	listContainer := container.NewVBox()
-	for i := range groups {
-		grp := groups[i]
-		colorRect := canvas.NewRectangle(parseHexColor(grp.ColorHex))
-		colorRect.SetMinSize(fyne.NewSize(20, 20))
-		lbl := widget.NewLabel(grp.Name)
-		btnEdit := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() { d.Hide(); showGroupForm(&grp) })
-		btnDel := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
-			dialog.ShowConfirm("Delete Group", "Delete '"+grp.Name+"'?", func(ok bool) {
-				if ok {
-					newGroups := []Group{}
-					for _, g := range groups {
-						if g.ID != grp.ID {
-							newGroups = append(newGroups, g)
-						}
-					}
-					groups = newGroups
-					saveGroups()
-					updateGroupDropdown()
-					if sbGroupSelect.Selected == grp.Name {
-						if len(groups) > 0 {
-							sbGroupSelect.SetSelected(groups[0].Name)
-						} else {
-							sbGroupSelect.SetSelected("")
-						}
-					}
-					refreshCalendar()
-					refreshKanban()
-					d.Hide()
-					showGroupManager()
-				}
-			}, mainWindow)
-		})
-		listContainer.Add(container.NewBorder(nil, nil, colorRect, container.NewHBox(btnEdit, btnDel), lbl))
+
+func migrateItems(items []TodoItem, from int, groups []Group) {
+	for v := max(from, 0); v < dataVersion && v < len(itemMigrations); v++ {
+		itemMigrations[v](items, groups)
 	}
-	scroll := container.NewVScroll(listContainer)
-	scroll.SetMinSize(fyne.NewSize(300, 400))
-	d = dialog.NewCustom("Groups", "Close", scroll, mainWindow)
-	d.Resize(fyne.NewSize(350, 500))
-	d.Show()
 }
-func showGroupForm(existingGroup *Group) {
-	var d dialog.Dialog
-	isEdit := existingGroup != nil
-	nameEntry := widget.NewEntry()
-	nameEntry.PlaceHolder = "e.g. Work"
-	defaultColor := PresetColors[0]
-	if isEdit {
-		nameEntry.SetText(existingGroup.Name)
-		defaultColor = existingGroup.ColorHex
+
+// readItemsFile loads and migrates a calendar's items, returning the version the file was written with.
+func readItemsFile(path string, groups []Group) ([]TodoItem, int, error) {
+	var f itemsFile
+	if err := readJSONFile(path, &f); err != nil {
+		return nil, 0, err
 	}
-	selectedColor := defaultColor
-	previewRect := canvas.NewRectangle(parseHexColor(selectedColor))
-	previewRect.SetMinSize(fyne.NewSize(50, 20))
-	colorGrid := container.NewGridWithColumns(6)
-	for _, c := range PresetColors {
-		hex := c
-		rect := canvas.NewRectangle(parseHexColor(hex))
-		rect.SetMinSize(fyne.NewSize(30, 30))
-		rect.StrokeColor = color.White
-		rect.StrokeWidth = 1
-		clickable := newClickableBox(container.NewStack(rect), func() { selectedColor = hex; previewRect.FillColor = parseHexColor(hex); previewRect.Refresh() })
-		colorGrid.Add(clickable)
+	if f.Version > dataVersion {
+		log.Printf("%s was written by a newer version (%d); unknown fields will be dropped on save", path, f.Version)
 	}
-	btnLabel := "Create Group"
-	if isEdit {
-		btnLabel = "Save Changes"
+	migrateItems(f.Items, f.Version, groups)
+	if f.Items == nil {
+		f.Items = []TodoItem{}
 	}
-	actionBtn := widget.NewButton(btnLabel, func() {
-		if nameEntry.Text == "" {
-			return
+	return f.Items, f.Version, nil
+}
+func marshalItems(items []TodoItem) []byte {
+	data, _ := json.MarshalIndent(itemsFile{Version: dataVersion, Items: items}, "", " ")
+	return data
+}
+
+const uncategorizedGroupName = "Uncategorized"
+
+// Inbox items have no group at all; the column exists only while the Inbox setting is on.
+const inboxGroupName = "Inbox"
+
+var inboxGroup = Group{Name: inboxGroupName, ColorHex: "#7F8C8D"}
+
+// recoverOrphanedItems moves items whose group no longer exists into an "Uncategorized" group, so they still show up on the board.
+func recoverOrphanedItems() {
+	if !groupsLoaded {
+		return
+	}
+	known := map[string]bool{"": appSettings.InboxEnabled}
+	for _, g := range groups {
+		known[g.ID] = true
+	}
+	orphans := []int{}
+	pruned := false
+	for i := range items {
+		ids := itemGroupIDs(&items[i])
+		live := slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return !known[id] })
+		switch {
+		case len(live) == 0:
+			orphans = append(orphans, i)
+		case len(live) < len(ids):
+			setItemGroups(&items[i], live)
+			pruned = true
 		}
-		targetName := nameEntry.Text
-		if isEdit {
-			for i, g := range groups {
-				if g.ID == existingGroup.ID {
-					groups[i].Name = nameEntry.Text
-					groups[i].ColorHex = selectedColor
-					break
-				}
-			}
-		} else {
-			newGroup := Group{ID: fmt.Sprintf("g-%d", time.Now().UnixNano()), Name: nameEntry.Text, ColorHex: selectedColor}
-			groups = append(groups, newGroup)
-			targetName = newGroup.Name
+	}
+	if len(orphans) == 0 {
+		if pruned {
+			saveData()
 		}
-		saveGroups()
-		updateGroupDropdown()
-		refreshCalendar()
-		refreshKanban()
-		sbGroupSelect.SetSelected(targetName)
-		if d != nil {
-			d.Hide()
+		return
+	}
+	fallbackID := groupIDForName(uncategorizedGroupName)
+	for _, i := range orphans {
+		setItemGroups(&items[i], []string{fallbackID})
+		items[i].GroupName = ""
+	}
+	saveData()
+}
+func parseItemTime(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+}
+
+// validateItemDates hides items whose Start or End can't be parsed, rather than letting them land in year 0001, and tells the user which ones.
+func validateItemDates() {
+	invalidDateIDs = make(map[string]bool)
+	bad := []string{}
+	for _, item := range items {
+		_, errS := parseItemTime(item.Start)
+		_, errE := parseItemTime(item.End)
+		if errS == nil && errE == nil {
+			continue
 		}
-	})
-	content := container.NewVBox(widget.NewLabel("Group Name:"), nameEntry, widget.NewLabel("Group Color:"), previewRect, colorGrid, layout.NewSpacer(), actionBtn)
-	d = dialog.NewCustom("Group", "Cancel", container.NewPadded(content), mainWindow)
-	d.Resize(fyne.NewSize(300, 400))
-	d.Show()
+		log.Printf("item %s (%q) has unreadable dates: start=%q end=%q", item.ID, item.Title, item.Start, item.End)
+		invalidDateIDs[item.ID] = true
+		bad = append(bad, fmt.Sprintf("'%s'", item.Title))
+	}
+	if len(bad) == 0 {
+		return
+	}
+	if len(bad) > 5 {
+		bad = append(bad[:5], fmt.Sprintf("and %d more", len(bad)-5))
+	}
+	dialog.ShowInformation("Unreadable Dates", fmt.Sprintf("%d item(s) have malformed dates and are hidden: %s", len(invalidDateIDs), strings.Join(bad, ", ")), mainWindow)
 }
-func performSmartDelete(targetID string) {
-	var targetItem *TodoItem
-	for i := range items {
-		if items[i].ID == targetID {
-			targetItem = &items[i]
-			break
+
+// --- REMINDERS ---
+
+var reminderOptions = []int{0, 5, 10, 15, 30, 60, 1440}
+
+func reminderLabel(min int) string {
+	switch {
+	case min <= 0:
+		return "None"
+	case min%1440 == 0:
+		if min == 1440 {
+			return "1 day before"
+		}
+		return fmt.Sprintf("%d days before", min/1440)
+	case min%60 == 0:
+		if min == 60 {
+			return "1 hour before"
+		}
+		return fmt.Sprintf("%d hours before", min/60)
+	}
+	return fmt.Sprintf("%d minutes before", min)
+}
+func reminderLabels() []string {
+	labels := []string{}
+	for _, m := range reminderOptions {
+		labels = append(labels, reminderLabel(m))
+	}
+	return labels
+}
+func reminderMinutesFromLabel(label string) int {
+	for _, m := range reminderOptions {
+		if reminderLabel(m) == label {
+			return m
 		}
 	}
-	if targetItem == nil {
+	return 0
+}
+
+// reminderKey includes the start and lead time so rescheduling an item re-arms its reminder.
+func reminderKey(item TodoItem) string {
+	return fmt.Sprintf("%s|%s|%d", item.ID, item.Start, item.ReminderMinutes)
+}
+func reminderTimes(item TodoItem) (fire, start time.Time, ok bool) {
+	if item.ReminderMinutes <= 0 || item.Completed || item.Archived {
 		return
 	}
-	var d dialog.Dialog
-	finish := func() {
-		saveData()
-		refreshCalendar()
-		refreshKanban()
-		resetSidebar()
-		if d != nil {
-			d.Hide()
+	start, err := parseItemTime(item.Start)
+	if err != nil {
+		return
+	}
+	return start.Add(-time.Duration(item.ReminderMinutes) * time.Minute), start, true
+}
+
+// reminderState is persisted in reminders_state.json so dismissed reminders stay quiet across restarts and
+// snoozed ones come back when due. Both maps are keyed by reminderKey.
+type reminderState struct {
+	Fired   map[string]time.Time `json:"fired"`
+	Snoozed map[string]time.Time `json:"snoozed,omitempty"`
+}
+
+const reminderSnooze = 10 * time.Minute
+
+func loadReminderState() {
+	_ = readJSONFile(dataPath("reminders_state.json"), &reminders)
+	if reminders.Fired == nil {
+		reminders.Fired = map[string]time.Time{}
+	}
+	if reminders.Snoozed == nil {
+		reminders.Snoozed = map[string]time.Time{}
+	}
+}
+
+// saveReminderState drops fired entries older than a month; their items are long past.
+func saveReminderState() {
+	cutoff := time.Now().AddDate(0, -1, 0)
+	for key, at := range reminders.Fired {
+		if at.Before(cutoff) {
+			delete(reminders.Fired, key)
 		}
 	}
-	if targetItem.SeriesID == "" {
-		dialog.ShowConfirm("Delete", "Delete?", func(ok bool) {
-			if ok {
-				newItems := []TodoItem{}
-				for _, i := range items {
-					if i.ID != targetID {
-						newItems = append(newItems, i)
-					}
+	file, _ := json.MarshalIndent(reminders, "", " ")
+	_ = writeFileAtomic(dataPath("reminders_state.json"), file)
+}
+
+// missedReminderGrace is how far back a reminder that came due while the app was closed is still shown.
+const missedReminderGrace = 12 * time.Hour
+
+// seedFiredReminders marks reminders that were already due when the data was loaded, so a restart doesn't repeat them.
+// The ones that came due within missedReminderGrace are shown once instead of being dropped.
+func seedFiredReminders() {
+	now := time.Now()
+	changed := false
+	var missed []TodoItem
+	for _, item := range items {
+		key := reminderKey(item)
+		if _, done := reminders.Fired[key]; done {
+			continue
+		}
+		if fire, _, ok := reminderTimes(item); ok && !fire.After(now) {
+			if _, snoozed := reminders.Snoozed[key]; !snoozed {
+				reminders.Fired[key] = now
+				changed = true
+				if now.Sub(fire) <= missedReminderGrace && !item.Completed {
+					missed = append(missed, item)
 				}
-				items = newItems
-				finish()
 			}
-		}, mainWindow)
+		}
+	}
+	if changed {
+		saveReminderState()
+	}
+	if len(missed) > 0 && mainWindow != nil {
+		showMissedReminders(missed)
+	}
+}
+
+// showMissedReminders gives a single missed reminder the normal prompt and summarizes several in one dialog.
+func showMissedReminders(missed []TodoItem) {
+	if len(missed) == 1 {
+		_, start, _ := reminderTimes(missed[0])
+		notifyReminder(missed[0], start, reminderKey(missed[0]))
 		return
 	}
-	d = dialog.NewCustom("Delete Recurring", "Cancel", container.NewVBox(widget.NewLabel("Repeating item. Delete?"), widget.NewButton("This Only", func() {
-		newItems := []TodoItem{}
-		for _, i := range items {
-			if i.ID != targetID {
-				newItems = append(newItems, i)
-			}
-		}
-		items = newItems
-		finish()
-	}), widget.NewButton("This + Future", func() {
-		targetTime, _ := time.ParseInLocation("2006-01-02 15:04", targetItem.Start, time.Local)
-		newItems := []TodoItem{}
-		for _, i := range items {
-			iTime, _ := time.ParseInLocation("2006-01-02 15:04", i.Start, time.Local)
-			if i.SeriesID != targetItem.SeriesID || iTime.Before(targetTime) {
-				newItems = append(newItems, i)
-			}
+	slices.SortFunc(missed, func(a, b TodoItem) int { return strings.Compare(a.Start, b.Start) })
+	lines := []string{}
+	for _, item := range missed {
+		start, _ := parseItemTime(item.Start)
+		when := formatDate(start, "Mon, Jan 2") + " " + formatClock(start)
+		if item.AllDay {
+			when = formatDate(start, "Mon, Jan 2")
 		}
-		items = newItems
-		finish()
-	}), widget.NewButton("All", func() {
-		newItems := []TodoItem{}
-		for _, i := range items {
-			if i.SeriesID != targetItem.SeriesID {
-				newItems = append(newItems, i)
-			}
-		}
-		items = newItems
-		finish()
-	})), mainWindow)
+		lines = append(lines, fmt.Sprintf("• %s — %s", item.Title, when))
+	}
+	myApp.SendNotification(fyne.NewNotification(fmt.Sprintf("%d missed reminders", len(missed)), strings.Join(lines, "\n")))
+	label := widget.NewLabel(strings.Join(lines, "\n"))
+	label.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustom("Missed Reminders", "Dismiss", container.NewVScroll(label), mainWindow)
+	d.Resize(fyne.NewSize(420, 320))
 	d.Show()
 }
-func importICS() {
-	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
-		if err != nil || reader == nil {
-			return
-		}
-		defer reader.Close()
-		data, _ := io.ReadAll(reader)
-		parsedCal, err := ical.ParseCalendar(strings.NewReader(string(data)))
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("failed to parse"), mainWindow)
-			return
-		}
-		count := 0
-		targetGroupID := ""
-		if len(groups) > 0 {
-			targetGroupID = groups[0].ID
-		}
-		for _, event := range parsedCal.Events() {
-			sum := event.GetProperty(ical.ComponentPropertySummary)
-			start := event.GetProperty(ical.ComponentPropertyDtStart)
-			end := event.GetProperty(ical.ComponentPropertyDtEnd)
-			if sum == nil || start == nil {
+func checkReminders() {
+	now := time.Now()
+	changed := false
+	for _, item := range items {
+		fire, start, ok := reminderTimes(item)
+		key := reminderKey(item)
+		if until, snoozed := reminders.Snoozed[key]; snoozed {
+			if ok && now.Before(until) {
 				continue
 			}
-			title := sum.Value
-			sTime, err := time.Parse("20060102T150405", start.Value)
-			if err != nil {
-				sTime, _ = time.Parse("20060102", start.Value)
-			}
-			eTime := sTime
-			if end != nil {
-				eTime, _ = time.Parse("20060102T150405", end.Value)
-				if eTime.IsZero() {
-					eTime, _ = time.Parse("20060102", end.Value)
-				}
-			}
-			iType := TypeTask
-			if !eTime.Equal(sTime) && !eTime.IsZero() {
-				iType = TypeEvent
+			delete(reminders.Snoozed, key)
+			changed = true
+			if ok {
+				notifyReminder(item, start, key)
 			}
-			items = append(items, TodoItem{ID: fmt.Sprintf("imp-%d-%d", time.Now().UnixNano(), count), Title: title, Start: sTime.Format("2006-01-02 15:04"), End: eTime.Format("2006-01-02 15:04"), Type: iType, GroupID: targetGroupID})
-			count++
+			continue
 		}
-		saveData()
-		refreshCalendar()
-		refreshKanban()
-		dialog.ShowInformation("Imported", fmt.Sprintf("%d items", count), mainWindow)
-	}, mainWindow)
-	fd.SetFilter(storage.NewExtensionFileFilter([]string{".ics"}))
-	fd.Show()
+		if _, done := reminders.Fired[key]; !ok || fire.After(now) || done {
+			continue
+		}
+		reminders.Fired[key] = now
+		changed = true
+		if !start.After(now) {
+			continue
+		}
+		notifyReminder(item, start, key)
+	}
+	if changed {
+		saveReminderState()
+	}
 }
-func exportICS() {
-	cal := ical.NewCalendar()
-	cal.SetMethod(ical.MethodPublish)
-	gName := make(map[string]string)
-	for _, g := range groups {
-		gName[g.ID] = g.Name
+
+// notifyReminder sends the system notification and, since those can't carry buttons, an in-app prompt to snooze or dismiss.
+func notifyReminder(item TodoItem, start time.Time, key string) {
+	now := time.Now()
+	body := fmt.Sprintf("Starts at %s", formatClock(start))
+	if item.Type == TypeTask {
+		body = fmt.Sprintf("Due at %s", formatClock(start))
 	}
-	for _, item := range items {
-		evt := cal.AddEvent(item.ID)
-		s, _ := time.ParseInLocation("2006-01-02 15:04", item.Start, time.Local)
-		e, _ := time.ParseInLocation("2006-01-02 15:04", item.End, time.Local)
-		evt.SetStartAt(s)
-		evt.SetEndAt(e)
-		evt.SetSummary(fmt.Sprintf("[%s] %s", gName[item.GroupID], item.Title))
+	if item.AllDay {
+		body = "Today"
+		if start.YearDay() != now.YearDay() || start.Year() != now.Year() {
+			body = formatDate(start, "Mon, Jan 2")
+		}
 	}
-	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-		if err != nil || writer == nil {
-			return
+	myApp.SendNotification(fyne.NewNotification(item.Title, body))
+	var d dialog.Dialog
+	snoozeBtn := widget.NewButtonWithIcon("Snooze 10 min", theme.HistoryIcon(), func() {
+		reminders.Snoozed[key] = time.Now().Add(reminderSnooze)
+		saveReminderState()
+		d.Hide()
+	})
+	dismissBtn := widget.NewButtonWithIcon("Dismiss", theme.ConfirmIcon(), func() { d.Hide() })
+	dismissBtn.Importance = widget.HighImportance
+	d = dialog.NewCustomWithoutButtons("Reminder", container.NewVBox(
+		widget.NewLabelWithStyle(item.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(body),
+		container.NewGridWithColumns(2, snoozeBtn, dismissBtn),
+	), mainWindow)
+	d.Show()
+}
+
+// startClockTicker wakes on each minute boundary to fire reminders and advance the now line.
+func startClockTicker() {
+	go func() {
+		time.Sleep(time.Until(time.Now().Truncate(time.Minute).Add(time.Minute)))
+		tick := func() {
+			checkReminders()
+			updateNowLine()
 		}
-		_, _ = writer.Write([]byte(cal.Serialize()))
-		_ = writer.Close()
-		dialog.ShowInformation("Success", "Exported", mainWindow)
-	}, mainWindow)
-	saveDialog.SetFileName("my_calendar.ics")
-	saveDialog.Show()
+		fyne.Do(tick)
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(tick)
+		}
+	}()
 }
-func createDatePickerButton(parent fyne.Window, onChanged func(string)) (*widget.Button, func() string, func(string)) {
-	selectedDate := time.Now()
-	btn := widget.NewButton(selectedDate.Format("2006-01-02"), nil)
-	setDate := func(dateStr string) {
-		t, err := time.Parse("2006-01-02", dateStr)
-		if err == nil {
-			selectedDate = t
-			btn.SetText(dateStr)
+
+// findConflicts returns the timed events, other than item itself, whose range overlaps item's.
+func findConflicts(item TodoItem) []TodoItem {
+	if item.Type != TypeEvent || item.AllDay {
+		return nil
+	}
+	s, err1 := parseItemTime(item.Start)
+	e, err2 := parseItemTime(item.End)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+	conflicts := []TodoItem{}
+	for _, other := range items {
+		if other.ID == item.ID || other.Type != TypeEvent || other.AllDay || other.Archived || other.Availability == AvailabilityFree {
+			continue
+		}
+		otherStart, _ := parseItemTime(other.Start)
+		otherEnd, _ := parseItemTime(other.End)
+		if otherStart.Before(e) && s.Before(otherEnd) {
+			conflicts = append(conflicts, other)
 		}
 	}
-	btn.OnTapped = func() {
-		navDate := selectedDate
-		isUpdating := false
-		startYear, endYear := 1900, 2100
-		years := []string{}
-		for i := startYear; i <= endYear; i++ {
-			years = append(years, strconv.Itoa(i))
-		}
-		grid := container.NewGridWithColumns(7)
-		yearList := widget.NewList(func() int { return len(years) }, func() fyne.CanvasObject {
-			return widget.NewLabelWithStyle("2000", fyne.TextAlignCenter, fyne.TextStyle{})
-		}, func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(years[i]) })
-		monthSelect := widget.NewSelect([]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}, nil)
-		yearBtn := widget.NewButton("Year", nil)
-		mainContent := container.NewStack(grid, container.NewScroll(yearList))
-		var d dialog.Dialog
-		var refreshPopup func()
-		yearBtn.OnTapped = func() {
-			if grid.Visible() {
-				grid.Hide()
-				yearList.Show()
-				idx := navDate.Year() - startYear
-				yearList.ScrollTo(idx)
-				yearList.Select(idx)
-			} else {
-				yearList.Hide()
-				grid.Show()
-				refreshPopup()
-			}
+	sort.Slice(conflicts, func(a, b int) bool { return conflicts[a].Start < conflicts[b].Start })
+	return conflicts
+}
+
+// sidebarAvailability stores Busy as the empty default so existing data files don't change.
+func sidebarAvailability() Availability {
+	if sbTypeSelect.Selected == string(TypeEvent) && sbShowAsSelect.Selected == string(AvailabilityFree) {
+		return AvailabilityFree
+	}
+	return ""
+}
+
+const workdayStart, workdayEnd = 8 * time.Hour, 18 * time.Hour
+
+// freeTimeOnDay is the time between 08:00 and 18:00 not covered by visible busy, timed events.
+// All-day events are left out, since they're usually reminders such as birthdays rather than blocked time.
+func freeTimeOnDay(day time.Time) time.Duration {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	winStart, winEnd := day.Add(workdayStart), day.Add(workdayEnd)
+	type span struct{ from, to time.Time }
+	busy := []span{}
+	for i := range items {
+		it := &items[i]
+		if it.Type != TypeEvent || it.AllDay || it.Availability == AvailabilityFree || !isVisible(it) {
+			continue
 		}
-		yearList.OnSelected = func(id widget.ListItemID) {
-			navDate = time.Date(startYear+id, navDate.Month(), 1, 0, 0, 0, 0, time.Local)
-			yearList.Hide()
-			grid.Show()
-			refreshPopup()
+		s, err1 := parseItemTime(it.Start)
+		e, err2 := parseItemTime(it.End)
+		if err1 != nil || err2 != nil || !s.Before(winEnd) || !e.After(winStart) {
+			continue
 		}
-		refreshPopup = func() {
-			isUpdating = true
-			monthSelect.SetSelectedIndex(int(navDate.Month()) - 1)
-			yearBtn.SetText(strconv.Itoa(navDate.Year()))
-			grid.Objects = nil
-			y, m, _ := navDate.Date()
-			first := time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
-			off := int(first.Weekday())
-			if off == 0 {
-				off = 7
-			}
-			off--
-			dim := first.AddDate(0, 1, -1).Day()
-			for _, day := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
-				grid.Add(widget.NewLabelWithStyle(day, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
-			}
-			for i := 0; i < off; i++ {
-				grid.Add(layout.NewSpacer())
-			}
-			for i := 1; i <= dim; i++ {
-				dayNum := i
-				dayBtn := widget.NewButton(strconv.Itoa(i), func() {
-					selectedDate = time.Date(y, m, dayNum, 0, 0, 0, 0, time.Local)
-					dateStr := selectedDate.Format("2006-01-02")
-					btn.SetText(dateStr)
-					if onChanged != nil {
-						onChanged(dateStr)
-					}
-					if d != nil {
-						d.Hide()
-					}
-				})
-				if y == time.Now().Year() && m == time.Now().Month() && i == time.Now().Day() {
-					dayBtn.Importance = widget.HighImportance
-				}
-				grid.Add(dayBtn)
-			}
-			isUpdating = false
-			grid.Refresh()
+		if s.Before(winStart) {
+			s = winStart
 		}
-		btnPrev := widget.NewButton("<", func() { navDate = navDate.AddDate(0, -1, 0); refreshPopup() })
-		btnNext := widget.NewButton(">", func() { navDate = navDate.AddDate(0, 1, 0); refreshPopup() })
-		monthSelect.OnChanged = func(s string) {
-			if isUpdating {
-				return
+		if e.After(winEnd) {
+			e = winEnd
+		}
+		busy = append(busy, span{s, e})
+	}
+	sort.Slice(busy, func(a, b int) bool { return busy[a].from.Before(busy[b].from) })
+	free := winEnd.Sub(winStart)
+	cursor := winStart
+	for _, b := range busy {
+		if b.to.After(cursor) {
+			from := b.from
+			if from.Before(cursor) {
+				from = cursor
 			}
-			for i, m := range monthSelect.Options {
-				if m == s {
-					navDate = navDate.AddDate(0, (i+1)-int(navDate.Month()), 0)
-					refreshPopup()
-					break
-				}
+			free -= b.to.Sub(from)
+			cursor = b.to
+		}
+	}
+	return free
+}
+func freeTimeLabel(day time.Time) string {
+	free := freeTimeOnDay(day)
+	if free <= 0 {
+		return "No free time"
+	}
+	return formatDuration(free) + " free"
+}
+func updateConflictWarning(item TodoItem) {
+	conflicts := findConflicts(item)
+	if len(conflicts) == 0 {
+		sbConflictLabel.Hide()
+		return
+	}
+	first := conflicts[0]
+	s, _ := parseItemTime(first.Start)
+	e, _ := parseItemTime(first.End)
+	msg := fmt.Sprintf("Conflicts with '%s' %s–%s", first.Title, formatClock(s), formatClock(e))
+	if len(conflicts) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(conflicts)-1)
+	}
+	sbConflictLabel.SetText(msg)
+	sbConflictLabel.Show()
+}
+func setCompleted(item *TodoItem, done bool) {
+	if done && !item.Completed && advanceRecurring(item) {
+		return
+	}
+	if done && !item.Completed {
+		item.CompletedAt = time.Now().Format("2006-01-02 15:04")
+	}
+	if !done {
+		item.CompletedAt = ""
+	}
+	item.Completed = done
+}
+func formatAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+// humanizeDate names dates within a week of today relatively and falls back to the absolute date beyond that.
+func humanizeDate(t time.Time) string {
+	switch days := daysBetween(time.Now(), t); {
+	case days == 0:
+		return "Today"
+	case days == 1:
+		return "Tomorrow"
+	case days == -1:
+		return "Yesterday"
+	case days > 1 && days < 7:
+		return fmt.Sprintf("In %d days", days)
+	case days < -1 && days > -7:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+	return formatDate(t, "Mon, Jan 02")
+}
+
+// itemTooltip is the hover preview text: full title, when, group, and whatever extra detail the item carries.
+func itemTooltip(item *TodoItem) string {
+	s, _ := parseItemTime(item.Start)
+	e, _ := parseItemTime(item.End)
+	when := formatDate(s, "Mon, Jan 2") + " " + formatClock(s)
+	switch {
+	case item.AllDay && daysBetween(s, e) > 0:
+		when = formatDate(s, "Mon, Jan 2") + " – " + formatDate(e, "Mon, Jan 2")
+	case item.AllDay:
+		when = formatDate(s, "Mon, Jan 2") + ", all day"
+	case item.Type == TypeEvent && daysBetween(s, e) > 0:
+		when += " – " + formatDate(e, "Mon, Jan 2") + " " + formatClock(e)
+	case item.Type == TypeEvent:
+		when += " – " + formatClock(e)
+	}
+	lines := []string{seriesMark(item) + item.Title, when}
+	if i := slices.IndexFunc(groups, func(g Group) bool { return g.ID == item.GroupID }); i >= 0 {
+		name := groups[i].Name
+		if p := groupParent(groups[i]); p != nil {
+			name = p.Name + " › " + name
+		}
+		lines = append(lines, "Group: "+name)
+	}
+	if item.Priority != "" && item.Priority != PriorityMedium {
+		lines = append(lines, "Priority: "+string(item.Priority))
+	}
+	if len(item.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(item.Tags, ", "))
+	}
+	if len(item.Subtasks) > 0 {
+		done := 0
+		for _, st := range item.Subtasks {
+			if st.Done {
+				done++
 			}
 		}
-		navBar := container.NewBorder(nil, nil, btnPrev, btnNext, container.NewGridWithColumns(2, monthSelect, yearBtn))
-		yearList.Hide()
-		refreshPopup()
-		d = dialog.NewCustom("Select Date", "Cancel", container.NewPadded(container.NewBorder(navBar, nil, nil, nil, mainContent)), parent)
-		d.Resize(fyne.NewSize(350, 400))
-		d.Show()
+		lines = append(lines, fmt.Sprintf("Checklist: %d/%d", done, len(item.Subtasks)))
 	}
-	return btn, func() string { return btn.Text }, setDate
+	if len(item.Attachments) > 0 {
+		lines = append(lines, fmt.Sprintf("Attachments: %d", len(item.Attachments)))
+	}
+	switch {
+	case item.Completed:
+		lines = append(lines, "Completed")
+	case isOverdue(item):
+		lines = append(lines, "OVERDUE")
+	}
+	return strings.Join(lines, "\n")
 }
-func createTimePicker(onChange func()) (*widget.Select, *widget.Select, *widget.Select, *fyne.Container, func(string, string, string)) {
-	hours := []string{}
-	for i := 1; i <= 12; i++ {
-		hours = append(hours, fmt.Sprintf("%02d", i))
+
+// advanceRecurring rolls a self-repeating item on to its next future date instead of completing it; false once the rule has run out.
+// Every occurrence stepped over on the way (missed while the item sat overdue) uses up one of a COUNT rule's occurrences.
+func advanceRecurring(item *TodoItem) bool {
+	rule := item.RecurrenceRule
+	if rule == nil {
+		return false
 	}
-	mins := []string{}
-	for i := 0; i < 60; i += 5 {
-		mins = append(mins, fmt.Sprintf("%02d", i))
+	if rule.Anchor == "" {
+		rule.Anchor = item.Start
 	}
-	h := widget.NewSelect(hours, func(s string) {
-		if onChange != nil {
-			onChange()
+	anchor, err := parseItemTime(rule.Anchor)
+	cur, err2 := parseItemTime(item.Start)
+	end, _ := parseItemTime(item.End)
+	if err != nil || err2 != nil {
+		item.RecurrenceRule = nil
+		return false
+	}
+	duration := end.Sub(cur)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	advanced := false
+	// Steps are counted from the anchor; the item's own time of day is kept in case it was rescheduled.
+	for k, t := 0, anchor; k < 100000; k++ {
+		t = nextOccurrence(anchor, t, *rule, k)
+		next := time.Date(t.Year(), t.Month(), t.Day(), cur.Hour(), cur.Minute(), 0, 0, time.Local)
+		if !next.After(cur) {
+			continue
 		}
-	})
-	m := widget.NewSelect(mins, func(s string) {
-		if onChange != nil {
-			onChange()
+		if !rule.Until.IsZero() && next.After(rule.Until) {
+			break
 		}
-	})
-	ap := widget.NewSelect([]string{"AM", "PM"}, func(s string) {
-		if onChange != nil {
-			onChange()
+		cur, advanced = next, true
+		if rule.Count > 0 {
+			rule.Count--
 		}
-	})
-	h.SetSelected("09")
-	m.SetSelected("00")
-	ap.SetSelected("AM")
-	setTime := func(hh, mm, ampm string) { h.SetSelected(hh); m.SetSelected(mm); ap.SetSelected(ampm) }
-	return h, m, ap, container.NewGridWithColumns(3, h, m, ap), setTime
+		if (rule.Count > 0 && rule.Count <= 1) || !next.Before(today) {
+			break
+		}
+	}
+	if !advanced {
+		item.RecurrenceRule = nil
+		return false
+	}
+	if rule.Count > 0 && rule.Count <= 1 {
+		item.RecurrenceRule = nil
+	}
+	item.Start, item.End = cur.Format("2006-01-02 15:04"), cur.Add(duration).Format("2006-01-02 15:04")
+	item.CompletedAt = now.Format("2006-01-02 15:04")
+	for i := range item.Subtasks {
+		item.Subtasks[i].Done = false
+	}
+	if item.ID == currentEditItemID {
+		loadSidebarItem(item)
+	}
+	return true
 }
-func updateGroupDropdown() {
-	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
-	options := []string{}
-	for _, g := range groups {
-		options = append(options, g.Name)
+
+// seriesMark flags occurrences of a recurring series, whose edits may apply to every occurrence.
+func seriesMark(item *TodoItem) string {
+	if item.SeriesID != "" || item.RecurrenceRule != nil {
+		return "↻ "
 	}
-	options = append(options, "+ Create New Group")
-	sbGroupSelect.Options = options
-	sbGroupSelect.Refresh()
+	return ""
 }
-func loadGroups() {
-	_, groupFile := getFilenames()
-	file, err := os.ReadFile(groupFile)
-	if err == nil {
-		_ = json.Unmarshal(file, &groups)
+func isOverdue(item *TodoItem) bool {
+	if item.Type != TypeTask || item.Completed {
+		return false
 	}
-	if len(groups) == 0 && os.IsNotExist(err) {
-		groups = []Group{{"g-1", "Work", "#3498DB", ""}, {"g-2", "Personal", "#2ECC71", ""}}
-		saveGroups()
+	due, err := parseItemTime(item.Start)
+	if err != nil {
+		return false
+	}
+	if item.AllDay {
+		due = due.AddDate(0, 0, 1)
 	}
+	return due.Before(time.Now())
 }
-func saveGroups() {
-	_, groupFile := getFilenames()
-	file, _ := json.MarshalIndent(groups, "", " ")
-	_ = os.WriteFile(groupFile, file, 0644)
+func priorityRank(p Priority) int {
+	switch p {
+	case PriorityHigh:
+		return 3
+	case PriorityLow:
+		return 1
+	}
+	return 2
 }
-func saveData() {
-	dataFile, _ := getFilenames()
-	file, _ := json.MarshalIndent(items, "", " ")
-	_ = os.WriteFile(dataFile, file, 0644)
+func icsPriority(p Priority) int {
+	switch p {
+	case PriorityHigh:
+		return 1
+	case PriorityLow:
+		return 9
+	}
+	return 5
 }
-func loadData() {
-	dataFile, _ := getFilenames()
-	file, err := os.ReadFile(dataFile)
-	if err == nil {
-		_ = json.Unmarshal(file, &items)
-		for i := range items {
-			if items[i].GroupID == "" && items[i].GroupName != "" {
-				for _, g := range groups {
-					if g.Name == items[i].GroupName {
-						items[i].GroupID = g.ID
-						break
-					}
-				}
-			}
-		}
+func dimColor(c color.Color) color.Color {
+	r, g, b, _ := c.RGBA()
+	return color.NRGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 128}
+}
+func normalizeHexColor(s string) (string, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(s, 16, 32); err != nil {
+		return "", false
+	}
+	return "#" + strings.ToUpper(s), true
+}
+
+// rememberCustomColor adds a non-preset color to the saved palette, keeping the most recent dozen.
+func rememberCustomColor(hex string) {
+	if slices.Contains(PresetColors, hex) || slices.Contains(appSettings.CustomColors, hex) {
+		return
+	}
+	appSettings.CustomColors = append(appSettings.CustomColors, hex)
+	if len(appSettings.CustomColors) > 12 {
+		appSettings.CustomColors = appSettings.CustomColors[len(appSettings.CustomColors)-12:]
 	}
+	saveSettings()
 }
 func parseHexColor(s string) color.Color {
 	if len(s) > 0 && s[0] == '#' {
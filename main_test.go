@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalDAVPayloadsKeepSeriesOverrides(t *testing.T) {
+	series := []TodoItem{
+		{ID: "a", Title: "Standup", Start: "2026-03-02 09:00", End: "2026-03-02 09:15", Type: TypeEvent, SeriesID: "s1"},
+		{ID: "b", Title: "Standup (moved room)", Start: "2026-03-09 09:00", End: "2026-03-09 09:15", Type: TypeEvent, SeriesID: "s1"},
+		{ID: "c", Title: "Standup", Start: "2026-03-16 09:00", End: "2026-03-16 09:15", Type: TypeEvent, SeriesID: "s1"},
+	}
+	payloads := calDAVPayloads(buildICSCalendar(series))
+	if len(payloads) != 1 {
+		t.Fatalf("got %d payloads, want one resource for the series", len(payloads))
+	}
+	body, ok := payloads["s1"]
+	if !ok {
+		t.Fatalf("payload not keyed by series UID: %v", payloads)
+	}
+	if !strings.Contains(body, "RRULE:") || !strings.Contains(body, "RECURRENCE-ID") {
+		t.Fatalf("payload lost the master or the override:\n%s", body)
+	}
+	parsed, _, _, err := parseICSItems([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(series) {
+		t.Fatalf("round trip gave %d occurrences, want %d", len(parsed), len(series))
+	}
+	for _, p := range parsed {
+		want := "Standup"
+		if p.Start == "2026-03-09 09:00" {
+			want = "Standup (moved room)"
+		}
+		if p.Title != want || p.SeriesID == "" {
+			t.Errorf("occurrence %s: got %q (series %q), want %q in a series", p.Start, p.Title, p.SeriesID, want)
+		}
+	}
+}
+
+func TestInferSeriesRuleRoundTrip(t *testing.T) {
+	cases := []struct {
+		start string
+		rule  recurrenceRule
+		want  string
+	}{
+		{"2026-01-13 10:00", recurrenceRule{Mode: "Monthly Weekday", Interval: 1, Weekday: "Tuesday", Nth: 2}, "FREQ=MONTHLY;INTERVAL=1;BYDAY=2TU;COUNT=13"},
+		{"2026-01-27 10:00", recurrenceRule{Mode: "Monthly Weekday", Interval: 1, Weekday: "Tuesday", Nth: -1}, "FREQ=MONTHLY;INTERVAL=1;BYDAY=-1TU;COUNT=13"},
+		{"2026-01-05 08:00", recurrenceRule{Mode: recModeWeekdays, Count: 10}, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;COUNT=10"},
+		{"2026-01-03 08:00", recurrenceRule{Mode: recModeWeekends, Count: 6}, "FREQ=WEEKLY;BYDAY=SA,SU;COUNT=6"},
+		{"2026-01-31 09:00", recurrenceRule{Mode: "Interval", Interval: 1, Unit: "Month(s)", Count: 4}, "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=-1;COUNT=4"},
+		{"2026-01-30 09:00", recurrenceRule{Mode: "Interval", Interval: 1, Unit: "Month(s)", Count: 4}, "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=28,29,30;BYSETPOS=-1;COUNT=4"},
+		{"2026-01-07 09:00", recurrenceRule{Mode: "Interval", Interval: 2, Unit: "Week(s)", Count: 5}, "FREQ=WEEKLY;INTERVAL=2;COUNT=5"},
+	}
+	for _, c := range cases {
+		base := TodoItem{ID: "base", Title: "x", Start: c.start, End: c.start, SeriesID: "s"}
+		series := append([]TodoItem{base}, generateOccurrences(base, c.rule)...)
+		starts := make([]time.Time, len(series))
+		for i, it := range series {
+			starts[i], _ = parseItemTime(it.Start)
+		}
+		got, exdates, ok := inferSeriesRule(starts)
+		if !ok || got != c.want || len(exdates) != 0 {
+			t.Errorf("%s %+v: got %q (%d exdates, ok=%v), want %q", c.start, c.rule, got, len(exdates), ok, c.want)
+			continue
+		}
+		parsed, ok := parseRRule(got)
+		if !ok {
+			t.Errorf("parseRRule(%q) failed", got)
+			continue
+		}
+		again := append([]TodoItem{base}, generateOccurrences(base, parsed)...)
+		if len(again) != len(series) {
+			t.Errorf("%q expands to %d occurrences, want %d", got, len(again), len(series))
+			continue
+		}
+		for i := range again {
+			if again[i].Start != series[i].Start {
+				t.Errorf("%q occurrence %d: got %s, want %s", got, i, again[i].Start, series[i].Start)
+			}
+		}
+	}
+}
+
+func TestParseICSItemsSkipsUnreadableTimes(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\n" +
+		"BEGIN:VEVENT\r\nUID:good\r\nSUMMARY:Good\r\nDTSTART:20260302T090000Z\r\nDTEND:20260302T100000Z\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:bad-start\r\nSUMMARY:Bad start\r\nDTSTART:2026-03-02\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:bad-end\r\nSUMMARY:Bad end\r\nDTSTART:20260302T090000Z\r\nDTEND:soon\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	parsed, _, skipped, err := parseICSItems([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 1 || parsed[0].Title != "Good" || skipped != 2 {
+		t.Fatalf("got %d items, %d skipped; want only Good and 2 skipped", len(parsed), skipped)
+	}
+}
+
+func TestAdvanceRecurringStepsFromAnchor(t *testing.T) {
+	rule := recurrenceRule{Mode: "Interval", Interval: 1, Unit: "Month(s)", Anchor: "2030-01-31 09:00"}
+	item := TodoItem{ID: "m", Start: "2030-02-28 09:00", End: "2030-02-28 10:00", RecurrenceRule: &rule}
+	if !advanceRecurring(&item) || item.Start != "2030-03-31 09:00" || item.End != "2030-03-31 10:00" {
+		t.Fatalf("got %s – %s, want the 31st again after February", item.Start, item.End)
+	}
+
+	start := time.Now().AddDate(0, 0, -70).Format("2006-01-02") + " 08:00"
+	weekly := recurrenceRule{Mode: "Interval", Interval: 1, Unit: "Week(s)", Count: 5}
+	item = TodoItem{ID: "w", Start: start, End: start, RecurrenceRule: &weekly}
+	if !advanceRecurring(&item) {
+		t.Fatal("weekly item did not advance")
+	}
+	want, _ := parseItemTime(start)
+	if item.Start != want.AddDate(0, 0, 28).Format("2006-01-02 15:04") || item.RecurrenceRule != nil {
+		t.Fatalf("got %s (rule %+v), want to stop on the 5th and last occurrence", item.Start, item.RecurrenceRule)
+	}
+}
+
+func TestIsSkippedOccurrenceMatchesByUID(t *testing.T) {
+	saved := items
+	defer func() { items = saved }()
+	items = []TodoItem{{ID: "a", Title: "Standup", Start: "2026-03-02 09:00", SeriesID: "s-1", SourceUID: "team@example.com", Exceptions: []string{"2026-03-09 09:00"}}}
+	renamed := TodoItem{Title: "Daily sync", Start: "2026-03-09 09:00"}
+	if !isSkippedOccurrence(renamed, "team@example.com") {
+		t.Error("renamed occurrence of the same series was not recognised as skipped")
+	}
+	if isSkippedOccurrence(TodoItem{Title: "Standup", Start: "2026-03-09 09:00"}, "other@example.com") {
+		t.Error("same title and start from another series was treated as skipped")
+	}
+}